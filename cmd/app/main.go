@@ -12,8 +12,10 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/api/handler"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/calendar"
 	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config"
 	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/migrate"
 	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
 	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/service"
 	"github.com/CharlesTenorioDev/b3-trade-aggregator/pkg/server"
@@ -48,9 +50,33 @@ func main() {
 	}
 	logger.Info("Pool de conexões PostgreSQL estabelecido com sucesso!")
 
+	// Recusa iniciar a API contra um schema anterior às migrações embutidas no binário.
+	migrator, err := migrate.NewEmbedded(pool)
+	if err != nil {
+		logger.Error("Falha ao carregar migrações embutidas", err)
+		return
+	}
+	if err = migrator.Verify(context.Background()); err != nil {
+		logger.Error("Schema do banco de dados incompatível", err)
+		return
+	}
+
+	// Calendário de pregões da B3 (embutido ou informado em B3_HOLIDAYS_FILE)
+	tradingCalendar, err := calendar.LoadFile(cfg.B3HolidaysFile)
+	if err != nil {
+		logger.Error("Falha ao carregar calendário de pregões", err, zap.String("file", cfg.B3HolidaysFile))
+		return
+	}
+	if !tradingCalendar.Covers(time.Now()) {
+		logger.Info("⚠️ Calendário de pregões não cobre o ano corrente; atualize holidays.csv ou B3_HOLIDAYS_FILE",
+			zap.Ints("covered_years", tradingCalendar.Years()))
+	}
+
 	// Configurar dependências para consultas (sem ingestão)
 	tradeRepo := repository.NewPostgresTradeRepository(pool)
-	tradeService := service.NewTradeService(nil, tradeRepo)
+	tradeService := service.NewTradeService(nil, tradeRepo, tradingCalendar, service.IngestionOptions{})
+	calendarService := service.NewCalendarService(tradingCalendar)
+	brokerService := service.NewBrokerService(repository.NewPostgresBrokerRepository(pool))
 
 	r := chi.NewRouter()
 
@@ -79,6 +105,8 @@ func main() {
 	})
 
 	handler.RegisterTradeAPIHandlers(r, tradeService)
+	handler.RegisterCalendarAPIHandlers(r, calendarService)
+	handler.RegisterBrokerAPIHandlers(r, brokerService)
 
 	srv := server.NewHTTPServer(r, cfg)
 
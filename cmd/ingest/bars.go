@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/service"
+)
+
+// runBars implementa "bars export".
+func runBars(args []string) int {
+	if len(args) == 0 || args[0] != "export" {
+		fmt.Println("Uso: ingest bars export -ticker T -date YYYY-MM-DD -type tick|volume|dollar -threshold N [-session S] [-out arquivo.csv]")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("bars export", flag.ExitOnError)
+	ticker := fs.String("ticker", "", "Código do instrumento (obrigatório)")
+	date := fs.String("date", "", "Data do pregão YYYY-MM-DD (obrigatório)")
+	barType := fs.String("type", service.BarTypeTick, "Tipo de barra: tick, volume ou dollar")
+	threshold := fs.String("threshold", "", "Negociações, ações ou R$ por barra (obrigatório)")
+	session := fs.String("session", "", "Tipo de sessão: regular, after_market ou código (padrão: todas)")
+	out := fs.String("out", "", "Arquivo CSV de saída (padrão: saída padrão)")
+	fs.Parse(args[1:])
+
+	if *ticker == "" || *date == "" || *threshold == "" {
+		fs.Usage()
+		return 2
+	}
+
+	ctx := context.Background()
+	_, pool, err := connectDatabase(ctx)
+	if err != nil {
+		return 1
+	}
+	defer pool.Close()
+
+	if err := verifySchema(ctx, pool); err != nil {
+		logger.Error("❌ Schema do banco de dados incompatível", err)
+		return 1
+	}
+
+	tradeService := service.NewTradeService(nil, repository.NewPostgresTradeRepository(pool), nil, service.IngestionOptions{})
+	series, err := tradeService.RetrieveEventBars(ctx, *ticker, *barType, *threshold, *date, *session)
+	if err != nil {
+		logger.Error("❌ Falha ao construir barras", err)
+		return 1
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			logger.Error("❌ Falha ao criar arquivo de saída", err)
+			return 1
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if err := writeBarsCSV(w, series.Bars); err != nil {
+		logger.Error("❌ Falha ao gravar CSV", err)
+		return 1
+	}
+	if *out != "" {
+		fmt.Printf("✅ %d barras '%s' de %s exportadas para %s\n", len(series.Bars), *barType, *ticker, *out)
+	}
+	return 0
+}
+
+// writeBarsCSV grava as barras em CSV com cabeçalho.
+func writeBarsCSV(w io.Writer, bars []entity.Bar) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"start", "end", "open", "high", "low", "close", "volume", "financial_volume", "trade_count", "vwap"})
+
+	formatPrice := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	for _, bar := range bars {
+		cw.Write([]string{
+			bar.Start.Format(time.RFC3339Nano),
+			bar.End.Format(time.RFC3339Nano),
+			formatPrice(bar.Open),
+			formatPrice(bar.High),
+			formatPrice(bar.Low),
+			formatPrice(bar.Close),
+			strconv.FormatInt(bar.Volume, 10),
+			formatPrice(bar.FinancialVolume),
+			strconv.FormatInt(bar.TradeCount, 10),
+			formatPrice(bar.VWAP),
+		})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
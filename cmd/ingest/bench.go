@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/benchmark"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
+)
+
+// runBench implementa "bench aggregated".
+func runBench(args []string) int {
+	if len(args) == 0 || args[0] != "aggregated" {
+		fmt.Println("Uso: ingest bench aggregated [-tickers N] [-days N] [-trades-per-day N] [-hot-factor N] [-iterations N] [-min-speedup X] [-out DIR] [-keep]")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("bench aggregated", flag.ExitOnError)
+	opts := benchmark.AggregatedOptions{}
+	fs.IntVar(&opts.Tickers, "tickers", 400, "Número de instrumentos gerados")
+	fs.IntVar(&opts.Days, "days", 60, "Número de pregões gerados")
+	fs.IntVar(&opts.TradesPerDay, "trades-per-day", 500, "Negociações por dia de cada instrumento")
+	fs.IntVar(&opts.HotFactor, "hot-factor", 100, "Multiplicador de negociações do instrumento mais líquido")
+	fs.IntVar(&opts.Iterations, "iterations", 5, "Execuções medidas por variante de consulta")
+	fs.Float64Var(&opts.MinSpeedup, "min-speedup", 10, "Ganho mínimo exigido das consultas atuais sobre a legada")
+	fs.StringVar(&opts.OutputDir, "out", "bench", "Diretório onde os planos de execução são gravados")
+	fs.BoolVar(&opts.Keep, "keep", false, "Mantém o schema de benchmark ao final")
+	fs.Parse(args[1:])
+
+	if opts.Tickers <= 0 || opts.Days <= 0 || opts.TradesPerDay <= 0 || opts.HotFactor <= 0 || opts.Iterations <= 0 || opts.MinSpeedup <= 0 {
+		fmt.Println("❌ Todos os parâmetros numéricos devem ser positivos")
+		return 2
+	}
+	opts.StartDate = time.Now().AddDate(0, 0, -opts.Days*2).Truncate(24 * time.Hour)
+
+	cfg := config.LoadConfig()
+	fmt.Printf("🧪 Populando schema '%s': %d instrumentos, %d pregões, %d negociações/dia (%s x%d)\n",
+		benchmark.Schema, opts.Tickers, opts.Days, opts.TradesPerDay, benchmark.HotTicker, opts.HotFactor)
+
+	report, err := benchmark.RunAggregated(context.Background(), cfg.DatabaseURL, opts)
+	if err != nil && report == nil {
+		logger.Error("❌ Falha no benchmark da consulta agregada", err)
+		return 1
+	}
+
+	fmt.Printf("   %d negociações geradas; consultando %s desde %s\n\n", report.SeededRows, report.HotTicker, opts.StartDate.Format("2006-01-02"))
+	for _, v := range report.Variants {
+		fmt.Printf("   %-18s mediana %10s  melhor %10s  %6.1fx  (%.4f, %d)  plano: %s\n",
+			v.Name, v.Median.Round(time.Microsecond), v.Best.Round(time.Microsecond),
+			v.Speedup, v.MaxRangeValue, v.MaxDailyVolume, v.PlanFile)
+	}
+
+	if err != nil {
+		logger.Error("❌ Benchmark reprovado", err)
+		return 1
+	}
+	fmt.Printf("\n✅ Todas as variantes retornaram o mesmo resultado, com ganho de ao menos %.1fx\n", opts.MinSpeedup)
+	return 0
+}
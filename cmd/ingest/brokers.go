@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/service"
+)
+
+// runBrokers implementa "brokers load" e "brokers list".
+func runBrokers(args []string) int {
+	if len(args) == 0 || (args[0] != "load" && args[0] != "list") {
+		fmt.Println("Uso: ingest brokers load -file corretoras.csv")
+		fmt.Println("     ingest brokers list")
+		return 2
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("brokers "+action, flag.ExitOnError)
+	file := fs.String("file", "", "Arquivo 'codigo;nome' com as corretoras (obrigatório em load)")
+	fs.Parse(args[1:])
+
+	if action == "load" && *file == "" {
+		fs.Usage()
+		return 2
+	}
+
+	ctx := context.Background()
+	_, pool, err := connectDatabase(ctx)
+	if err != nil {
+		return 1
+	}
+	defer pool.Close()
+
+	if err := verifySchema(ctx, pool); err != nil {
+		logger.Error("❌ Schema do banco de dados incompatível", err)
+		return 1
+	}
+
+	brokerService := service.NewBrokerService(repository.NewPostgresBrokerRepository(pool))
+
+	if action == "list" {
+		brokers, err := brokerService.ListBrokers(ctx)
+		if err != nil {
+			logger.Error("❌ Falha ao listar corretoras", err)
+			return 1
+		}
+		fmt.Printf("🏦 %d corretoras cadastradas:\n", len(brokers))
+		for _, b := range brokers {
+			fmt.Printf("   %6d  %s\n", b.ParticipantCode, b.Name)
+		}
+		return 0
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		logger.Error("❌ Falha ao abrir arquivo de corretoras", err)
+		return 1
+	}
+	defer f.Close()
+
+	count, err := brokerService.ImportBrokers(ctx, f)
+	if err != nil {
+		logger.Error("❌ Falha ao carregar corretoras", err)
+		return 1
+	}
+	fmt.Printf("✅ %d corretoras gravadas a partir de %s\n", count, *file)
+	return 0
+}
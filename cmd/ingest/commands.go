@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
+	"go.uber.org/zap"
+)
+
+// subcommands mapeia os nomes aceitos como primeiro argumento da CLI.
+var subcommands = map[string]func(args []string) int{
+	"bars":       runBars,
+	"bench":      runBench,
+	"brokers":    runBrokers,
+	"gaps":       runGaps,
+	"migrate":    runMigrate,
+	"outliers":   runOutliers,
+	"pairs":      runPairs,
+	"partitions": runPartitions,
+	"summaries":  runSummaries,
+	"verify":     runVerify,
+}
+
+// subcommandUsage descreve cada subcomando na ajuda da CLI.
+var subcommandUsage = map[string]string{
+	"bars":       "export -ticker T -date D -type T -threshold N  Exporta barras por negócios, quantidade ou R$ em CSV",
+	"bench":      "aggregated [-tickers N] [-days N] [-keep]      Mede a consulta agregada em dados sintéticos",
+	"brokers":    "load -file F | list                            Carrega ou lista o mapeamento código → corretora",
+	"gaps":       "check [-from D] [-to D]                        Verifica lacunas e repetições nos IDs de negócio",
+	"migrate":    "up | down [-steps N] | status                 Aplica, reverte ou lista as migrações do schema",
+	"outliers":   "detect [-from D] [-to D] [-block-multiple N]   Marca negócios em bloco e desvios de preço",
+	"pairs":      "export -ticker T -date D [-out-dir DIR]        Exporta a matriz comprador x vendedor e os self-trades",
+	"partitions": "list | prune [-retention-months N] [-dry-run]   Gerencia as partições mensais de 'trades'",
+	"summaries":  "rebuild [-from D] [-to D] [-all]               Recalcula daily_summaries a partir de 'trades'",
+	"verify":     "-file F                                        Relê o arquivo e reconcilia com 'trades' por ticker",
+}
+
+// printSubcommands imprime a lista de subcomandos disponíveis.
+func printSubcommands() {
+	names := make([]string, 0, len(subcommandUsage))
+	for name := range subcommandUsage {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("\nSubcomandos:")
+	for _, name := range names {
+		fmt.Printf("  %s %s\n", name, subcommandUsage[name])
+	}
+}
+
+// connectDatabase carrega a configuração e abre um pool de conexões já validado com Ping.
+func connectDatabase(ctx context.Context) (*config.Config, *pgxpool.Pool, error) {
+	cfg := config.LoadConfig()
+
+	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		logger.Error("Falha ao criar o pool de conexões", err, zap.String("database_url", cfg.DatabaseURL))
+		return nil, nil, err
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		logger.Error("Falha ao pingar o banco de dados", err)
+		pool.Close()
+		return nil, nil, err
+	}
+
+	return cfg, pool, nil
+}
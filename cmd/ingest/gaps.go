@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/service"
+)
+
+// runGaps implementa "gaps check".
+func runGaps(args []string) int {
+	if len(args) == 0 || args[0] != "check" {
+		fmt.Println("Uso: ingest gaps check [-from YYYY-MM-DD] [-to YYYY-MM-DD]")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("gaps check", flag.ExitOnError)
+	fromStr := fs.String("from", "", "Primeira data a verificar (YYYY-MM-DD, opcional)")
+	toStr := fs.String("to", "", "Última data a verificar (YYYY-MM-DD, opcional)")
+	fs.Parse(args[1:])
+
+	var from, to time.Time
+	var err error
+	if *fromStr != "" {
+		if from, err = time.Parse("2006-01-02", *fromStr); err != nil {
+			logger.Error("❌ Formato de '-from' inválido. Use YYYY-MM-DD", err)
+			return 2
+		}
+	}
+	if *toStr != "" {
+		if to, err = time.Parse("2006-01-02", *toStr); err != nil {
+			logger.Error("❌ Formato de '-to' inválido. Use YYYY-MM-DD", err)
+			return 2
+		}
+	}
+
+	ctx := context.Background()
+	_, pool, err := connectDatabase(ctx)
+	if err != nil {
+		return 1
+	}
+	defer pool.Close()
+
+	if err := verifySchema(ctx, pool); err != nil {
+		logger.Error("❌ Schema do banco de dados incompatível", err)
+		return 1
+	}
+
+	tradeService := service.NewTradeService(nil, repository.NewPostgresTradeRepository(pool), nil, service.IngestionOptions{})
+
+	checks, err := tradeService.CheckTradeIDs(ctx, from, to)
+	if err != nil {
+		logger.Error("❌ Falha ao verificar a completude dos negócios", err)
+		return 1
+	}
+
+	for _, check := range checks {
+		fmt.Printf("   🔎 %s: %d negócio(s), %d ausente(s) (%.4f%%), %d repetido(s), %d fora do incremento\n",
+			check.TradeDate.Format("2006-01-02"), check.Trades, check.Missing, check.MissingPct, check.Duplicates, check.Misaligned)
+		for _, issue := range check.Issues {
+			fmt.Printf("      %-12s %-10s %d-%d (%d)\n", issue.InstrumentCode, issue.Kind, issue.FirstID, issue.LastID, issue.Count)
+		}
+	}
+
+	fmt.Printf("✅ Completude verificada em %d data(s)\n", len(checks))
+	return 0
+}
@@ -72,6 +72,14 @@ func (pt *ProgressTracker) PrintProgress() {
 
 // main é o ponto de entrada da aplicação CLI.
 func main() {
+	// Subcomandos de manutenção (ex: "partitions") possuem flags próprias e
+	// são despachados antes do fluxo padrão de ingestão.
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			os.Exit(run(os.Args[2:]))
+		}
+	}
+
 	// Define o número máximo de núcleos de CPU a serem usados.
 	// 6 núcleos é um bom ponto de partida para balancear CPU e I/O.
 	runtime.GOMAXPROCS(6)
@@ -82,6 +90,14 @@ func main() {
 		filePath    = flag.String("file", "", "Caminho para o arquivo de dados da B3 (obrigatório, ou defina a variável de ambiente FILE_PATH)")
 		showVersion = flag.Bool("version", false, "Exibe informações da versão")
 		showHelp    = flag.Bool("help", false, "Exibe informações de ajuda")
+
+		ingestMode     = flag.String("mode", "", "Modo de gravação: 'stream' (COPY contínuo por worker) ou 'batch' (transação por lote)")
+		workers        = flag.Int("workers", 0, "Número de workers de gravação (padrão 4)")
+		commitInterval = flag.Int("commit-interval", 0, "Linhas por commit no modo 'stream' (padrão 100000)")
+		batchSize      = flag.Int("batch-size", 0, "Tamanho do lote no modo 'batch' (padrão 1000)")
+		detectOutliers = flag.Bool("detect-outliers", false, "Detecta outliers (blocos e desvios de preço) nas datas carregadas")
+		verifyLoad     = flag.Bool("verify", false, "Relê o arquivo após a carga e reconcilia com 'trades', marcando as datas como verificadas")
+		maxGapPct      = flag.Float64("max-gap-pct", 0, "Percentual máximo de negócios ausentes por data; acima dele a ingestão falha (0 apenas registra, mesmo com INGEST_MAX_GAP_PCT)")
 	)
 	flag.Parse() // Executa o parsing das flags
 
@@ -100,15 +116,29 @@ func main() {
 	// Se a flag --help foi solicitada ou nenhum arquivo foi especificado, exibe a ajuda e encerra.
 	if *showHelp || actualFilePath == "" {
 		fmt.Println("B3 Trade Aggregator - CLI de Ingestão de Dados")
-		fmt.Println("Uso: go run cmd/ingest/main.go -file <caminho_do_arquivo>")
-		fmt.Println("   ou: go run cmd/ingest/main.go (com a variável de ambiente FILE_PATH definida)")
+		fmt.Println("Uso: go run ./cmd/ingest -file <caminho_do_arquivo>")
+		fmt.Println("   ou: go run ./cmd/ingest (com a variável de ambiente FILE_PATH definida)")
+		fmt.Println("   ou: go run ./cmd/ingest <subcomando> [flags]")
 		fmt.Println("\nFlags:")
 		flag.PrintDefaults() // Imprime as flags padrão definidas
+		printSubcommands()
 		fmt.Println("\nVariáveis de Ambiente:")
-		fmt.Println("  FILE_PATH    Caminho para o arquivo de dados de negociações da B3")
+		fmt.Println("  FILE_PATH               Caminho para o arquivo de dados de negociações da B3")
+		fmt.Println("  INGEST_MODE             Modo de gravação padrão ('stream' ou 'batch')")
+		fmt.Println("  INGEST_WORKERS          Número de workers de gravação")
+		fmt.Println("  INGEST_COMMIT_INTERVAL  Linhas por commit no modo 'stream'")
+		fmt.Println("  INGEST_BATCH_SIZE       Tamanho do lote no modo 'batch'")
+		fmt.Println("  INGEST_DETECT_OUTLIERS  Detecta outliers nas datas carregadas (true/false)")
+		fmt.Println("  INGEST_MAX_GAP_PCT      % máximo de negócios ausentes por data (0 apenas registra)")
+		fmt.Println("  INGEST_VERIFY           Reconcilia o arquivo com 'trades' após a carga (true/false)")
+		fmt.Println("  TRADES_RETENTION_MONTHS Meses de partições mantidos por 'partitions prune'")
 		fmt.Println("\nExemplos:")
-		fmt.Println("  go run cmd/ingest/main.go -file data/29-08-2025_NEGOCIOSAVISTA.txt")
-		fmt.Println("  FILE_PATH=data/29-08-2025_NEGOCIOSAVISTA.txt go run cmd/ingest/main.go")
+		fmt.Println("  go run ./cmd/ingest -file data/29-08-2025_NEGOCIOSAVISTA.txt")
+		fmt.Println("  FILE_PATH=data/29-08-2025_NEGOCIOSAVISTA.txt go run ./cmd/ingest")
+		fmt.Println("  go run ./cmd/ingest -file data/test_sample.txt -mode batch")
+		fmt.Println("  go run ./cmd/ingest migrate up")
+		fmt.Println("  go run ./cmd/ingest verify -file data/test_sample.txt")
+		fmt.Println("  go run ./cmd/ingest partitions prune -retention-months 24 -dry-run")
 		os.Exit(0)
 	}
 
@@ -134,6 +164,41 @@ func main() {
 	// Carrega as configurações da aplicação (incluindo DATABASE_URL).
 	cfg := config.LoadConfig()
 
+	// Flags de linha de comando têm precedência sobre as variáveis de ambiente.
+	ingestOpts := service.IngestionOptions{
+		Workers:        cfg.INGEST_WORKERS,
+		Mode:           cfg.INGEST_MODE,
+		BatchSize:      cfg.INGEST_BATCH_SIZE,
+		CommitInterval: cfg.INGEST_COMMIT_INTERVAL,
+		DetectOutliers: cfg.INGEST_DETECT_OUTLIERS || *detectOutliers,
+		MaxGapPct:      cfg.INGEST_MAX_GAP_PCT,
+		Verify:         cfg.INGEST_VERIFY || *verifyLoad,
+	}
+	if *ingestMode != "" {
+		ingestOpts.Mode = *ingestMode
+	}
+	if *workers > 0 {
+		ingestOpts.Workers = *workers
+	}
+	if *commitInterval > 0 {
+		ingestOpts.CommitInterval = *commitInterval
+	}
+	if *batchSize > 0 {
+		ingestOpts.BatchSize = *batchSize
+	}
+	// Um limite zero também é válido (apenas registra), então o flag vale sempre que
+	// informado, mesmo como -max-gap-pct 0 sobre INGEST_MAX_GAP_PCT.
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "max-gap-pct" {
+			ingestOpts.MaxGapPct = *maxGapPct
+		}
+	})
+	ingestOpts = ingestOpts.WithDefaults()
+	if ingestOpts.Mode != service.IngestionModeStream && ingestOpts.Mode != service.IngestionModeBatch {
+		logger.Error("Modo de ingestão inválido", fmt.Errorf("modo desconhecido: %s", ingestOpts.Mode))
+		os.Exit(1)
+	}
+
 	// Inicializa a conexão com o banco de dados PostgreSQL usando pgxpool.
 	logger.Info("Conectando ao PostgreSQL...")
 	pool, err := pgxpool.New(context.Background(), cfg.DatabaseURL)
@@ -150,6 +215,12 @@ func main() {
 	}
 	logger.Info("✅ Conexão com PostgreSQL estabelecida com sucesso!")
 
+	// Recusa a ingestão se o schema estiver atrás das migrações embutidas no binário.
+	if err := verifySchema(context.Background(), pool); err != nil {
+		logger.Error("❌ Schema do banco de dados incompatível", err)
+		os.Exit(1)
+	}
+
 	// Inicializa o rastreador de progresso.
 	progressTracker := &ProgressTracker{
 		startTime:  time.Now(),
@@ -161,10 +232,13 @@ func main() {
 	// Passa o pool de conexões (pgxpool.Pool) para o repositório.
 	// O repositório deve ser adaptado para usar pgxpool.
 	tradeRepo := repository.NewPostgresTradeRepository(pool)
-	tradeService := service.NewTradeService(tradeReader, tradeRepo)
+	tradeService := service.NewTradeService(tradeReader, tradeRepo, nil, ingestOpts)
 
 	// Inicia o processo de ingestão de dados.
-	logger.Info("🚀 Iniciando o processo de ingestão de dados...")
+	logger.Info("🚀 Iniciando o processo de ingestão de dados...",
+		zap.String("mode", ingestOpts.Mode),
+		zap.Int("workers", ingestOpts.Workers),
+		zap.Int("commit_interval", ingestOpts.CommitInterval))
 	fmt.Println("📊 Monitoramento de progresso ativado...")
 
 	// Cria um contexto com timeout para a operação de ingestão.
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/migrate"
+)
+
+// runMigrate implementa "migrate up", "migrate down" e "migrate status".
+func runMigrate(args []string) int {
+	if len(args) == 0 || (args[0] != "up" && args[0] != "down" && args[0] != "status") {
+		fmt.Println("Uso: ingest migrate up")
+		fmt.Println("     ingest migrate down [-steps N]")
+		fmt.Println("     ingest migrate status")
+		return 2
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("migrate "+action, flag.ExitOnError)
+	steps := fs.Int("steps", 1, "Número de migrações revertidas por 'down'")
+	fs.Parse(args[1:])
+
+	ctx := context.Background()
+	_, pool, err := connectDatabase(ctx)
+	if err != nil {
+		return 1
+	}
+	defer pool.Close()
+
+	migrator, err := migrate.NewEmbedded(pool)
+	if err != nil {
+		logger.Error("❌ Falha ao carregar migrações embutidas", err)
+		return 1
+	}
+
+	switch action {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("   ⬆️  %03d_%s aplicada\n", m.Version, m.Name)
+		}
+		if err != nil {
+			logger.Error("❌ Falha ao aplicar migrações", err)
+			return 1
+		}
+		fmt.Printf("✅ Schema na versão %d (%d migração(ões) aplicada(s))\n", migrator.LatestVersion(), len(applied))
+
+	case "down":
+		reverted, err := migrator.Down(ctx, *steps)
+		for _, m := range reverted {
+			fmt.Printf("   ⬇️  %03d_%s revertida\n", m.Version, m.Name)
+		}
+		if err != nil {
+			logger.Error("❌ Falha ao reverter migrações", err)
+			return 1
+		}
+		fmt.Printf("✅ %d migração(ões) revertida(s)\n", len(reverted))
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			logger.Error("❌ Falha ao obter status das migrações", err)
+			return 1
+		}
+		fmt.Println("📋 Migrações:")
+		for _, s := range statuses {
+			if s.Applied {
+				fmt.Printf("   ✅ %03d_%s (aplicada em %s)\n", s.Version, s.Name, s.AppliedAt.Format("2006-01-02 15:04:05"))
+			} else {
+				fmt.Printf("   ⏳ %03d_%s (pendente)\n", s.Version, s.Name)
+			}
+		}
+	}
+
+	return 0
+}
+
+// verifySchema recusa a execução quando o banco está em uma versão anterior às
+// migrações embutidas no binário.
+func verifySchema(ctx context.Context, pool *pgxpool.Pool) error {
+	migrator, err := migrate.NewEmbedded(pool)
+	if err != nil {
+		return err
+	}
+	return migrator.Verify(ctx)
+}
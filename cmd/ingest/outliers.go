@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/service"
+)
+
+// runOutliers implementa "outliers detect".
+func runOutliers(args []string) int {
+	if len(args) == 0 || args[0] != "detect" {
+		fmt.Println("Uso: ingest outliers detect [-from YYYY-MM-DD] [-to YYYY-MM-DD] [-block-multiple N] [-history-days N] [-window N] [-deviation-pct P] [-deviation-sigma S]")
+		return 2
+	}
+
+	defaults := service.DefaultOutlierRules
+	fs := flag.NewFlagSet("outliers detect", flag.ExitOnError)
+	fromStr := fs.String("from", "", "Primeira data a processar (YYYY-MM-DD, opcional)")
+	toStr := fs.String("to", "", "Última data a processar (YYYY-MM-DD, opcional)")
+	blockMultiple := fs.Float64("block-multiple", defaults.BlockMultiple, "Múltiplo do tamanho médio por negócio que marca um bloco (0 desativa)")
+	historyDays := fs.Int("history-days", defaults.HistoryDays, "Dias corridos anteriores usados no tamanho médio por negócio")
+	window := fs.Int("window", defaults.PriceWindow, "Negócios anteriores da média móvel de preço")
+	deviationPct := fs.Float64("deviation-pct", defaults.DeviationPct, "Desvio percentual da média móvel que marca o preço (0 desativa)")
+	deviationSigma := fs.Float64("deviation-sigma", defaults.DeviationSigma, "Desvios padrão da média móvel que marcam o preço (0 desativa)")
+	fs.Parse(args[1:])
+
+	var from, to time.Time
+	var err error
+	if *fromStr != "" {
+		if from, err = time.Parse("2006-01-02", *fromStr); err != nil {
+			logger.Error("❌ Formato de '-from' inválido. Use YYYY-MM-DD", err)
+			return 2
+		}
+	}
+	if *toStr != "" {
+		if to, err = time.Parse("2006-01-02", *toStr); err != nil {
+			logger.Error("❌ Formato de '-to' inválido. Use YYYY-MM-DD", err)
+			return 2
+		}
+	}
+
+	ctx := context.Background()
+	_, pool, err := connectDatabase(ctx)
+	if err != nil {
+		return 1
+	}
+	defer pool.Close()
+
+	if err := verifySchema(ctx, pool); err != nil {
+		logger.Error("❌ Schema do banco de dados incompatível", err)
+		return 1
+	}
+
+	tradeService := service.NewTradeService(nil, repository.NewPostgresTradeRepository(pool), nil, service.IngestionOptions{})
+
+	detected, err := tradeService.DetectOutliers(ctx, from, to, repository.OutlierRules{
+		BlockMultiple:  *blockMultiple,
+		HistoryDays:    *historyDays,
+		PriceWindow:    *window,
+		DeviationPct:   *deviationPct,
+		DeviationSigma: *deviationSigma,
+	})
+	for _, date := range detected {
+		fmt.Printf("   🔎 %s processada\n", date.Format("2006-01-02"))
+	}
+	if err != nil {
+		logger.Error("❌ Falha ao detectar outliers", err)
+		return 1
+	}
+
+	fmt.Printf("✅ Outliers detectados em %d data(s)\n", len(detected))
+	return 0
+}
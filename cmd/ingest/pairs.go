@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/service"
+)
+
+// runPairs implementa "pairs export".
+func runPairs(args []string) int {
+	if len(args) == 0 || args[0] != "export" {
+		fmt.Println("Uso: ingest pairs export -ticker T -date YYYY-MM-DD [-session S] [-out-dir DIR]")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("pairs export", flag.ExitOnError)
+	ticker := fs.String("ticker", "", "Código do instrumento (obrigatório)")
+	date := fs.String("date", "", "Data do pregão YYYY-MM-DD (obrigatório)")
+	session := fs.String("session", "", "Tipo de sessão: regular, after_market ou código (padrão: todas)")
+	outDir := fs.String("out-dir", ".", "Diretório onde os CSVs são gravados")
+	fs.Parse(args[1:])
+
+	if *ticker == "" || *date == "" {
+		fs.Usage()
+		return 2
+	}
+
+	ctx := context.Background()
+	_, pool, err := connectDatabase(ctx)
+	if err != nil {
+		return 1
+	}
+	defer pool.Close()
+
+	if err := verifySchema(ctx, pool); err != nil {
+		logger.Error("❌ Schema do banco de dados incompatível", err)
+		return 1
+	}
+
+	tradeService := service.NewTradeService(nil, repository.NewPostgresTradeRepository(pool), nil, service.IngestionOptions{})
+	report, err := tradeService.RetrieveParticipantPairs(ctx, *ticker, *date, *session)
+	if err != nil {
+		logger.Error("❌ Falha ao montar relatório de pares", err)
+		return 1
+	}
+
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		logger.Error("❌ Falha ao criar diretório de saída", err)
+		return 1
+	}
+	prefix := filepath.Join(*outDir, fmt.Sprintf("%s_%s", *ticker, report.TradeDate.Format("2006-01-02")))
+
+	pairsFile, selfTradesFile := prefix+"_pairs.csv", prefix+"_self_trades.csv"
+	if err := writeCSVFile(pairsFile, func(w io.Writer) error { return writePairsCSV(w, report.Pairs) }); err != nil {
+		logger.Error("❌ Falha ao gravar matriz de pares", err)
+		return 1
+	}
+	if err := writeCSVFile(selfTradesFile, func(w io.Writer) error { return writeSelfTradesCSV(w, report.SelfTrades) }); err != nil {
+		logger.Error("❌ Falha ao gravar self-trades", err)
+		return 1
+	}
+
+	fmt.Printf("✅ %d pares exportados para %s\n", len(report.Pairs), pairsFile)
+	fmt.Printf("   %d self-trades (%d ações) exportados para %s\n", report.SelfTradeCount, report.SelfTradeVolume, selfTradesFile)
+	return 0
+}
+
+// writeCSVFile cria o arquivo e delega a escrita a 'write'.
+func writeCSVFile(path string, write func(io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writePairsCSV grava a matriz de pares comprador x vendedor em CSV com cabeçalho.
+func writePairsCSV(w io.Writer, pairs []entity.ParticipantPair) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"buyer", "buyer_name", "seller", "seller_name", "trade_count", "volume", "financial_volume", "volume_share", "self_trade"})
+
+	for _, p := range pairs {
+		cw.Write([]string{
+			strconv.Itoa(p.Buyer),
+			p.BuyerName,
+			strconv.Itoa(p.Seller),
+			p.SellerName,
+			strconv.FormatInt(p.TradeCount, 10),
+			strconv.FormatInt(p.Volume, 10),
+			strconv.FormatFloat(p.FinancialVolume, 'f', -1, 64),
+			strconv.FormatFloat(p.VolumeShare, 'f', -1, 64),
+			strconv.FormatBool(p.SelfTrade),
+		})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeSelfTradesCSV grava as negociações marcadas como self-trade em CSV com cabeçalho.
+func writeSelfTradesCSV(w io.Writer, trades []entity.SelfTrade) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"time", "price", "quantity", "participant", "name"})
+
+	for _, t := range trades {
+		cw.Write([]string{
+			t.Time.Format(time.RFC3339Nano),
+			strconv.FormatFloat(t.Price, 'f', -1, 64),
+			strconv.FormatInt(t.Quantity, 10),
+			strconv.Itoa(t.Participant),
+			t.Name,
+		})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/service"
+)
+
+// defaultRetentionMonths é usado quando nem a flag nem TRADES_RETENTION_MONTHS são informadas.
+const defaultRetentionMonths = 24
+
+// runPartitions implementa "partitions list" e "partitions prune".
+func runPartitions(args []string) int {
+	if len(args) == 0 || (args[0] != "list" && args[0] != "prune") {
+		fmt.Println("Uso: ingest partitions list")
+		fmt.Println("     ingest partitions prune [-retention-months N] [-dry-run]")
+		return 2
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("partitions "+action, flag.ExitOnError)
+	retentionMonths := fs.Int("retention-months", 0, fmt.Sprintf("Meses mantidos, incluindo o corrente (padrão TRADES_RETENTION_MONTHS ou %d)", defaultRetentionMonths))
+	dryRun := fs.Bool("dry-run", false, "Apenas lista as partições que seriam removidas")
+	fs.Parse(args[1:])
+
+	ctx := context.Background()
+	cfg, pool, err := connectDatabase(ctx)
+	if err != nil {
+		return 1
+	}
+	defer pool.Close()
+
+	if err := verifySchema(ctx, pool); err != nil {
+		logger.Error("❌ Schema do banco de dados incompatível", err)
+		return 1
+	}
+
+	partitionService := service.NewPartitionService(repository.NewPostgresPartitionRepository(pool))
+
+	if action == "list" {
+		partitions, err := partitionService.ListPartitions(ctx)
+		if err != nil {
+			logger.Error("❌ Falha ao listar partições", err)
+			return 1
+		}
+		fmt.Println("📦 Partições de 'trades':")
+		for _, p := range partitions {
+			fmt.Printf("   %-16s %s → %s  ~%d linhas\n", p.Name, p.From.Format("2006-01-02"), p.To.Format("2006-01-02"), p.EstimatedRows)
+		}
+		return 0
+	}
+
+	months := *retentionMonths
+	if months <= 0 {
+		months = cfg.TRADES_RETENTION_MONTHS
+	}
+	if months <= 0 {
+		months = defaultRetentionMonths
+	}
+
+	expired, err := partitionService.PrunePartitions(ctx, months, *dryRun)
+	for _, p := range expired {
+		if *dryRun {
+			fmt.Printf("   🔍 Seria removida: %s (%s → %s)\n", p.Name, p.From.Format("2006-01-02"), p.To.Format("2006-01-02"))
+		} else {
+			fmt.Printf("   🗑️  Removida: %s (%s → %s)\n", p.Name, p.From.Format("2006-01-02"), p.To.Format("2006-01-02"))
+		}
+	}
+	if err != nil {
+		logger.Error("❌ Falha ao aplicar retenção de partições", err)
+		return 1
+	}
+
+	fmt.Printf("✅ Retenção de %d meses aplicada: %d partição(ões) expirada(s)\n", months, len(expired))
+	return 0
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/service"
+)
+
+// runSummaries implementa "summaries rebuild".
+func runSummaries(args []string) int {
+	if len(args) == 0 || args[0] != "rebuild" {
+		fmt.Println("Uso: ingest summaries rebuild [-from YYYY-MM-DD] [-to YYYY-MM-DD] [-all]")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("summaries rebuild", flag.ExitOnError)
+	fromStr := fs.String("from", "", "Primeira data a recalcular (YYYY-MM-DD, opcional)")
+	toStr := fs.String("to", "", "Última data a recalcular (YYYY-MM-DD, opcional)")
+	all := fs.Bool("all", false, "Recalcula também as datas que já possuem resumos")
+	fs.Parse(args[1:])
+
+	var from, to time.Time
+	var err error
+	if *fromStr != "" {
+		if from, err = time.Parse("2006-01-02", *fromStr); err != nil {
+			logger.Error("❌ Formato de '-from' inválido. Use YYYY-MM-DD", err)
+			return 2
+		}
+	}
+	if *toStr != "" {
+		if to, err = time.Parse("2006-01-02", *toStr); err != nil {
+			logger.Error("❌ Formato de '-to' inválido. Use YYYY-MM-DD", err)
+			return 2
+		}
+	}
+
+	ctx := context.Background()
+	_, pool, err := connectDatabase(ctx)
+	if err != nil {
+		return 1
+	}
+	defer pool.Close()
+
+	if err := verifySchema(ctx, pool); err != nil {
+		logger.Error("❌ Schema do banco de dados incompatível", err)
+		return 1
+	}
+
+	tradeService := service.NewTradeService(nil, repository.NewPostgresTradeRepository(pool), nil, service.IngestionOptions{})
+
+	rebuilt, err := tradeService.RebuildDailySummaries(ctx, from, to, !*all)
+	for _, date := range rebuilt {
+		fmt.Printf("   🔁 %s recalculada\n", date.Format("2006-01-02"))
+	}
+	if err != nil {
+		logger.Error("❌ Falha ao recalcular resumos diários", err)
+		return 1
+	}
+
+	fmt.Printf("✅ Resumos diários recalculados para %d data(s)\n", len(rebuilt))
+	return 0
+}
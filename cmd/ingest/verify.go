@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/ingestion"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/service"
+)
+
+// runVerify implementa "verify -file F".
+func runVerify(args []string) int {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	filePath := fs.String("file", "", "Arquivo de negociações da B3 já carregado (obrigatório)")
+	fs.Parse(args)
+
+	if *filePath == "" {
+		fmt.Println("Uso: ingest verify -file <caminho_do_arquivo>")
+		return 2
+	}
+
+	ctx := context.Background()
+	_, pool, err := connectDatabase(ctx)
+	if err != nil {
+		return 1
+	}
+	defer pool.Close()
+
+	if err := verifySchema(ctx, pool); err != nil {
+		logger.Error("❌ Schema do banco de dados incompatível", err)
+		return 1
+	}
+
+	tradeService := service.NewTradeService(ingestion.NewTradeStreamReader(), repository.NewPostgresTradeRepository(pool), nil, service.IngestionOptions{})
+
+	verification, err := tradeService.VerifyIngestion(ctx, *filePath)
+	if err != nil {
+		logger.Error("❌ Falha ao reconciliar o arquivo", err)
+		return 1
+	}
+
+	for _, date := range verification.TradeDates {
+		fmt.Printf("   🔎 %s reconciliada\n", date.Format("2006-01-02"))
+	}
+	if verification.Rejected > 0 {
+		fmt.Printf("❌ %d de %d linha(s) de dados rejeitada(s) na leitura do arquivo; veja errors.log\n", verification.Rejected, verification.Lines)
+	}
+	if len(verification.Discrepancies) > 0 {
+		fmt.Printf("%-10s %-12s %12s %12s %16s %16s %22s %22s\n",
+			"data", "ticker", "linhas arq.", "linhas bd", "qtd. arq.", "qtd. bd", "preço x qtd. arq.", "preço x qtd. bd")
+		for _, d := range verification.Discrepancies {
+			fmt.Printf("%-10s %-12s %12d %12d %16d %16d %22d %22d\n",
+				d.TradeDate.Format("2006-01-02"), d.InstrumentCode,
+				d.File.Rows, d.Stored.Rows, d.File.Quantity, d.Stored.Quantity, d.File.NotionalTicks, d.Stored.NotionalTicks)
+		}
+		fmt.Printf("❌ %d ticker(s) divergente(s) em %d comparado(s)\n", len(verification.Discrepancies), verification.Tickers)
+	}
+	if !verification.Verified() {
+		return 1
+	}
+
+	fmt.Printf("✅ Carga verificada: %d negociações e %d ticker(s) conferidos\n", verification.Rows, verification.Tickers)
+	return 0
+}
@@ -2,11 +2,15 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
 	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/service"
+	"github.com/go-chi/chi/v5"
 )
 
 func GetAggregatedTradesHandler(tradeService service.TradeService) http.HandlerFunc {
@@ -18,16 +22,43 @@ func GetAggregatedTradesHandler(tradeService service.TradeService) http.HandlerF
 		}
 
 		startDateStr := r.URL.Query().Get("data_inicio")
+		endDateStr := r.URL.Query().Get("data_fim")
+
+		// Vários tickers separados por vírgula respondem no formato de lote.
+		if strings.Contains(instrumentCode, ",") {
+			if r.URL.Query().Get("fields") != "" {
+				http.Error(w, "Parâmetro 'fields' não é suportado em consultas com vários tickers.", http.StatusBadRequest)
+				return
+			}
+			var requests []service.AggregatedRequest
+			for _, ticker := range strings.Split(instrumentCode, ",") {
+				requests = append(requests, service.AggregatedRequest{Ticker: ticker, DataInicio: startDateStr, DataFim: endDateStr})
+			}
+			writeAggregatedBatch(w, r, tradeService, requests, r.URL.Query().Get("session"))
+			return
+		}
+
+		// 'fields' é opcional: sem ele a resposta mantém apenas os campos originais.
+		fields, err := parseAggregatedFields(r.URL.Query().Get("fields"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 
 		// Chama o serviço para obter os dados agregados
-		aggregatedData, err := tradeService.RetrieveAggregatedData(r.Context(), instrumentCode, startDateStr)
+		aggregatedData, err := tradeService.RetrieveAggregatedData(r.Context(), instrumentCode, startDateStr, endDateStr, r.URL.Query().Get("session"), len(fields) > 0)
 		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
 
-			if strings.Contains(err.Error(), "dados não encontrados") {
-				http.Error(w, "Dados não encontrados para o ticker e período especificados.", http.StatusNotFound)
+		if len(fields) > 0 && len(fields) < len(entity.AggregatedStatisticsFields) {
+			selected, err := selectAggregatedFields(aggregatedData, fields)
+			if err != nil {
+				http.Error(w, "Erro ao converter resposta para JSON", http.StatusInternalServerError)
 				return
 			}
-			http.Error(w, fmt.Sprintf("Erro interno ao consultar dados: %v", err), http.StatusInternalServerError)
+			writeJSON(w, selected)
 			return
 		}
 
@@ -40,6 +71,408 @@ func GetAggregatedTradesHandler(tradeService service.TradeService) http.HandlerF
 	}
 }
 
+// aggregatedBatchRequest é o corpo de POST /api/v1/trades/aggregated. As datas de
+// cada ticker, quando informadas, substituem as datas gerais; 'session' vale para todos.
+type aggregatedBatchRequest struct {
+	DataInicio string                      `json:"data_inicio"`
+	DataFim    string                      `json:"data_fim"`
+	Session    string                      `json:"session"`
+	Tickers    []service.AggregatedRequest `json:"tickers"`
+}
+
+// PostAggregatedTradesHandler responde POST /api/v1/trades/aggregated com os dados
+// agregados de uma lista de tickers, cada um com seu intervalo opcional.
+func PostAggregatedTradesHandler(tradeService service.TradeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body aggregatedBatchRequest
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&body); err != nil {
+			http.Error(w, fmt.Sprintf("Corpo da requisição inválido: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		requests := make([]service.AggregatedRequest, len(body.Tickers))
+		for i, request := range body.Tickers {
+			if request.DataInicio == "" {
+				request.DataInicio = body.DataInicio
+			}
+			if request.DataFim == "" {
+				request.DataFim = body.DataFim
+			}
+			requests[i] = request
+		}
+		writeAggregatedBatch(w, r, tradeService, requests, body.Session)
+	}
+}
+
+// writeAggregatedBatch executa a consulta em lote e responde {"results": [...]} na
+// ordem dos tickers recebidos.
+func writeAggregatedBatch(w http.ResponseWriter, r *http.Request, tradeService service.TradeService, requests []service.AggregatedRequest, session string) {
+	items, err := tradeService.RetrieveAggregatedDataBatch(r.Context(), requests, session)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, map[string][]entity.AggregatedBatchItem{"results": items})
+}
+
+// parseAggregatedFields valida o parâmetro 'fields' de /trades/aggregated: uma lista
+// separada por vírgulas de campos de entity.AggregatedStatisticsFields, ou "all".
+// Retorna os campos adicionais solicitados, sem repetições.
+func parseAggregatedFields(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	known := make(map[string]bool, len(entity.AggregatedStatisticsFields))
+	for _, field := range entity.AggregatedStatisticsFields {
+		known[field] = true
+	}
+
+	var fields []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		switch {
+		case field == "all":
+			return entity.AggregatedStatisticsFields, nil
+		case !known[field]:
+			return nil, fmt.Errorf("Parâmetro 'fields' inválido: campo '%s' desconhecido. Use 'all' ou: %s",
+				field, strings.Join(entity.AggregatedStatisticsFields, ", "))
+		case !seen[field]:
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// selectAggregatedFields mantém na resposta os campos originais e apenas os campos
+// adicionais solicitados.
+func selectAggregatedFields(data *entity.AggregatedData, fields []string) (map[string]json.RawMessage, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &all); err != nil {
+		return nil, err
+	}
+
+	selected := map[string]json.RawMessage{
+		"ticker":           all["ticker"],
+		"max_range_value":  all["max_range_value"],
+		"max_daily_volume": all["max_daily_volume"],
+	}
+	for _, field := range fields {
+		selected[field] = all[field]
+	}
+	return selected, nil
+}
+
+// GetDailyTradesHandler responde GET /api/v1/trades/{ticker}/daily?from=&to=&session=
+// com a série diária OHLCV do instrumento.
+func GetDailyTradesHandler(tradeService service.TradeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		series, err := tradeService.RetrieveDailySeries(r.Context(), chi.URLParam(r, "ticker"), query.Get("from"), query.Get("to"), query.Get("session"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, series)
+	}
+}
+
+// GetCandlesHandler responde GET /api/v1/trades/{ticker}/candles?interval=&date=&fill=&regular_only=&session=
+// com as barras de tempo do instrumento em um pregão.
+func GetCandlesHandler(tradeService service.TradeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		fill, err := parseBoolParam(query.Get("fill"))
+		if err != nil {
+			http.Error(w, "Parâmetro 'fill' inválido. Use true ou false.", http.StatusBadRequest)
+			return
+		}
+		regularOnly, err := parseBoolParam(query.Get("regular_only"))
+		if err != nil {
+			http.Error(w, "Parâmetro 'regular_only' inválido. Use true ou false.", http.StatusBadRequest)
+			return
+		}
+
+		interval := query.Get("interval")
+		if interval == "" {
+			interval = "5m"
+		}
+
+		series, err := tradeService.RetrieveCandles(r.Context(), chi.URLParam(r, "ticker"), interval, query.Get("date"), query.Get("session"), fill, regularOnly)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, series)
+	}
+}
+
+// GetEventBarsHandler responde GET /api/v1/trades/{ticker}/bars?type=tick|volume|dollar&threshold=&date=&session=
+// com as barras por evento do instrumento em um pregão.
+func GetEventBarsHandler(tradeService service.TradeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		series, err := tradeService.RetrieveEventBars(r.Context(), chi.URLParam(r, "ticker"), query.Get("type"), query.Get("threshold"), query.Get("date"), query.Get("session"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, series)
+	}
+}
+
+// GetPriceBenchmarksHandler responde GET /api/v1/trades/{ticker}/vwap-twap?from=&to=&start_time=&end_time=&session=
+// com VWAP e TWAP do instrumento no intervalo e na janela intradiária informados.
+func GetPriceBenchmarksHandler(tradeService service.TradeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		result, err := tradeService.RetrievePriceBenchmarks(r.Context(), chi.URLParam(r, "ticker"),
+			query.Get("from"), query.Get("to"), query.Get("start_time"), query.Get("end_time"), query.Get("session"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, result)
+	}
+}
+
+// GetBrokerFlowsHandler responde GET /api/v1/trades/{ticker}/brokers?from=&to=&limit=&session=
+// com os maiores compradores e vendedores, a posição líquida e a concentração por corretora.
+func GetBrokerFlowsHandler(tradeService service.TradeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		result, err := tradeService.RetrieveBrokerFlows(r.Context(), chi.URLParam(r, "ticker"),
+			query.Get("from"), query.Get("to"), query.Get("limit"), query.Get("session"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, result)
+	}
+}
+
+// GetParticipantPairsHandler responde GET /api/v1/trades/{ticker}/participant-pairs?date=&session=
+// com a matriz de pares comprador x vendedor e os self-trades do instrumento no pregão.
+func GetParticipantPairsHandler(tradeService service.TradeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		report, err := tradeService.RetrieveParticipantPairs(r.Context(), chi.URLParam(r, "ticker"), query.Get("date"), query.Get("session"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, report)
+	}
+}
+
+// GetSessionBreakdownHandler responde GET /api/v1/trades/{ticker}/sessions?from=&to=
+// com volume e amplitude de preço do instrumento por tipo de sessão.
+func GetSessionBreakdownHandler(tradeService service.TradeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		breakdown, err := tradeService.RetrieveSessionBreakdown(r.Context(), chi.URLParam(r, "ticker"), query.Get("from"), query.Get("to"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, breakdown)
+	}
+}
+
+// GetVolumeProfileHandler responde GET /api/v1/trades/{ticker}/volume-profile?date=&from=&to=&bucket_type=&bucket_size=&value_area=&session=
+// com o volume negociado por faixa de preço, o POC e a área de valor do instrumento.
+func GetVolumeProfileHandler(tradeService service.TradeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		profile, err := tradeService.RetrieveVolumeProfile(r.Context(), chi.URLParam(r, "ticker"), service.VolumeProfileRequest{
+			Date:       query.Get("date"),
+			From:       query.Get("from"),
+			To:         query.Get("to"),
+			BucketType: query.Get("bucket_type"),
+			BucketSize: query.Get("bucket_size"),
+			ValueArea:  query.Get("value_area"),
+			TickSize:   query.Get("tick_size"),
+			Session:    query.Get("session"),
+		})
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, profile)
+	}
+}
+
+// GetVolatilityHandler responde GET /api/v1/trades/{ticker}/volatility?from=&to=&window=&session=
+// com os log-retornos diários, as volatilidades realizadas e o drawdown do instrumento.
+func GetVolatilityHandler(tradeService service.TradeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		report, err := tradeService.RetrieveVolatility(r.Context(), chi.URLParam(r, "ticker"),
+			query.Get("from"), query.Get("to"), query.Get("window"), query.Get("session"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, report)
+	}
+}
+
+// GetIndicatorsHandler responde GET /api/v1/trades/{ticker}/indicators?name=&period=&std_dev=&interval=&date=&from=&to=&session=
+// com a série do indicador técnico sobre as barras diárias ou intradiárias do instrumento.
+func GetIndicatorsHandler(tradeService service.TradeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		series, err := tradeService.RetrieveIndicators(r.Context(), chi.URLParam(r, "ticker"), service.IndicatorRequest{
+			Name:     query.Get("name"),
+			Period:   query.Get("period"),
+			StdDev:   query.Get("std_dev"),
+			Interval: query.Get("interval"),
+			Date:     query.Get("date"),
+			From:     query.Get("from"),
+			To:       query.Get("to"),
+			Session:  query.Get("session"),
+		})
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, series)
+	}
+}
+
+// GetOutliersHandler responde GET /api/v1/alerts/outliers?date=&from=&to=&ticker=&kind=&limit=&session=
+// com as negociações marcadas pela detecção de outliers.
+func GetOutliersHandler(tradeService service.TradeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		report, err := tradeService.RetrieveOutliers(r.Context(), service.OutlierRequest{
+			Date:    query.Get("date"),
+			From:    query.Get("from"),
+			To:      query.Get("to"),
+			Ticker:  query.Get("ticker"),
+			Kind:    query.Get("kind"),
+			Session: query.Get("session"),
+			Limit:   query.Get("limit"),
+		})
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, report)
+	}
+}
+
+// GetRankingsHandler responde GET /api/v1/rankings?date=&from=&to=&metric=&limit=&asset_class=&session=
+// com os instrumentos de maiores e menores valores da métrica.
+func GetRankingsHandler(tradeService service.TradeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		metric := query.Get("metric")
+		if metric == "" {
+			metric = "volume"
+		}
+
+		ranking, err := tradeService.RetrieveRankings(r.Context(), service.RankingRequest{
+			Date:       query.Get("date"),
+			From:       query.Get("from"),
+			To:         query.Get("to"),
+			Metric:     metric,
+			Limit:      query.Get("limit"),
+			AssetClass: query.Get("asset_class"),
+			Session:    query.Get("session"),
+		})
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, ranking)
+	}
+}
+
+// PostScreenerHandler responde POST /api/v1/screener com os instrumentos cujas métricas
+// diárias atendem aos filtros do corpo (service.ScreenerRequest), paginados.
+func PostScreenerHandler(tradeService service.TradeService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body service.ScreenerRequest
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&body); err != nil {
+			http.Error(w, fmt.Sprintf("Corpo da requisição inválido: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		result, err := tradeService.RunScreener(r.Context(), body)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, result)
+	}
+}
+
+// GetCalendarHandler responde GET /api/v1/calendar?from=&to= com os pregões e
+// feriados da B3 no intervalo.
+func GetCalendarHandler(calendarService service.CalendarService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := calendarService.ListTradingDays(r.Context(), r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, result)
+	}
+}
+
+// GetBrokersHandler responde GET /api/v1/brokers com o mapeamento de códigos de
+// participante para nomes de corretoras.
+func GetBrokersHandler(brokerService service.BrokerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		brokers, err := brokerService.ListBrokers(r.Context())
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, map[string][]entity.Broker{"brokers": brokers})
+	}
+}
+
+// parseBoolParam interpreta um parâmetro booleano opcional; vazio equivale a false.
+func parseBoolParam(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// writeServiceError traduz erros do serviço em respostas HTTP: 400 para parâmetros
+// inválidos (service.ErrInvalidParameter), 404 quando não há dados e 500 nos demais casos.
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidParameter):
+		http.Error(w, strings.TrimPrefix(err.Error(), "service: "), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "dados não encontrados"):
+		http.Error(w, "Dados não encontrados para o ticker e período especificados.", http.StatusNotFound)
+	default:
+		http.Error(w, fmt.Sprintf("Erro interno ao consultar dados: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// writeJSON serializa a resposta como JSON.
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, "Erro ao converter resposta para JSON", http.StatusInternalServerError)
+	}
+}
+
 func CreateTradeHandler(tradeService service.TradeService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 
@@ -11,7 +11,32 @@ func RegisterTradeAPIHandlers(r *chi.Mux, tradeService service.TradeService) {
 
 		r.Route("/trades", func(r chi.Router) {
 			r.Get("/aggregated", GetAggregatedTradesHandler(tradeService))
+			r.Post("/aggregated", PostAggregatedTradesHandler(tradeService))
+			r.Get("/{ticker}/daily", GetDailyTradesHandler(tradeService))
+			r.Get("/{ticker}/candles", GetCandlesHandler(tradeService))
+			r.Get("/{ticker}/bars", GetEventBarsHandler(tradeService))
+			r.Get("/{ticker}/vwap-twap", GetPriceBenchmarksHandler(tradeService))
+			r.Get("/{ticker}/brokers", GetBrokerFlowsHandler(tradeService))
+			r.Get("/{ticker}/participant-pairs", GetParticipantPairsHandler(tradeService))
+			r.Get("/{ticker}/sessions", GetSessionBreakdownHandler(tradeService))
+			r.Get("/{ticker}/volume-profile", GetVolumeProfileHandler(tradeService))
+			r.Get("/{ticker}/volatility", GetVolatilityHandler(tradeService))
+			r.Get("/{ticker}/indicators", GetIndicatorsHandler(tradeService))
 
 		})
+
+		r.Get("/rankings", GetRankingsHandler(tradeService))
+		r.Post("/screener", PostScreenerHandler(tradeService))
+		r.Get("/alerts/outliers", GetOutliersHandler(tradeService))
 	})
 }
+
+// RegisterCalendarAPIHandlers registra as rotas do calendário de pregões.
+func RegisterCalendarAPIHandlers(r *chi.Mux, calendarService service.CalendarService) {
+	r.Get("/api/v1/calendar", GetCalendarHandler(calendarService))
+}
+
+// RegisterBrokerAPIHandlers registra as rotas do mapeamento de corretoras.
+func RegisterBrokerAPIHandlers(r *chi.Mux, brokerService service.BrokerService) {
+	r.Get("/api/v1/brokers", GetBrokersHandler(brokerService))
+}
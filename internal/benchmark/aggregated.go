@@ -0,0 +1,288 @@
+// Package benchmark contém harnesses que medem o desempenho das consultas da API
+// contra um volume sintético de dados, isolado em um schema próprio do banco.
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/migrate"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+)
+
+// Schema é o schema isolado onde o harness cria e popula suas tabelas.
+const Schema = "b3_bench"
+
+// legacySelfJoinQuery é a consulta original de GetAggregatedData, que junta cada
+// negociação do ticker ao subselect de volume diário. Mantida aqui apenas como
+// referência de desempenho e de resultado.
+const legacySelfJoinQuery = `
+        SELECT
+            MAX(t.negotiated_price) AS max_range_value,
+            MAX(daily_volume.total_volume) AS max_daily_volume
+        FROM
+            trades t
+        JOIN (
+            SELECT
+                trade_date,
+                SUM(negotiated_quantity) AS total_volume
+            FROM
+                trades
+            WHERE
+                instrument_code = $1 AND trade_date >= $2
+            GROUP BY
+                trade_date
+        ) AS daily_volume ON t.trade_date = daily_volume.trade_date AND t.instrument_code = $1
+        WHERE
+            t.instrument_code = $1 AND t.trade_date >= $2;
+    `
+
+// AggregatedOptions define o volume sintético e a forma de medição.
+type AggregatedOptions struct {
+	Tickers      int       // Número de instrumentos distintos
+	Days         int       // Número de pregões (dias úteis) gerados
+	TradesPerDay int       // Negociações por dia de cada instrumento comum
+	HotFactor    int       // Multiplicador de negociações do instrumento líquido (ex: PETR4)
+	StartDate    time.Time // Primeiro dia gerado
+	Iterations   int       // Execuções medidas por variante
+	OutputDir    string    // Diretório onde os planos de execução são gravados
+	MinSpeedup   float64   // Ganho mínimo (mediana legada / mediana da variante) exigido das consultas atuais
+	Keep         bool      // Mantém o schema de benchmark ao final
+}
+
+// VariantResult resume a medição de uma variante de consulta.
+type VariantResult struct {
+	Name           string
+	MaxRangeValue  float64
+	MaxDailyVolume int64
+	Median         time.Duration
+	Best           time.Duration
+	Speedup        float64 // Mediana da consulta legada dividida pela mediana desta variante
+	PlanFile       string
+}
+
+// AggregatedReport é o resultado completo do harness.
+type AggregatedReport struct {
+	SeededRows int64
+	HotTicker  string
+	Variants   []VariantResult
+}
+
+// HotTicker é o instrumento com maior liquidez no conjunto sintético.
+const HotTicker = "PETR4"
+
+// RunAggregated popula o schema de benchmark, mede a consulta legada, a consulta
+// reescrita sobre 'trades' e a consulta sobre daily_summaries para o instrumento
+// mais líquido, grava os planos (EXPLAIN ANALYZE) e verifica que todas as
+// variantes retornam o mesmo resultado e que as consultas atuais são ao menos
+// MinSpeedup vezes mais rápidas que a legada. Quando a verificação falha, o
+// relatório é retornado junto com o erro.
+func RunAggregated(ctx context.Context, databaseURL string, opts AggregatedOptions) (*AggregatedReport, error) {
+	poolConfig, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("benchmark: DATABASE_URL inválida: %w", err)
+	}
+	// Todas as consultas não qualificadas (inclusive as do repositório) resolvem para o schema isolado.
+	poolConfig.ConnConfig.RuntimeParams["search_path"] = Schema
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("benchmark: falha ao criar pool: %w", err)
+	}
+	defer pool.Close()
+
+	if err := createSchema(ctx, pool); err != nil {
+		return nil, err
+	}
+	if !opts.Keep {
+		defer pool.Exec(context.Background(), fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", Schema))
+	}
+
+	report := &AggregatedReport{HotTicker: HotTicker}
+	if report.SeededRows, err = seed(ctx, pool, opts); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(opts.OutputDir, 0o755); err != nil {
+		return nil, fmt.Errorf("benchmark: falha ao criar diretório de saída: %w", err)
+	}
+
+	// A consulta legada é aberta no fim; as atuais recebem data_fim, aqui o último dia gerado.
+	openEnded := []any{HotTicker, opts.StartDate}
+	bounded := []any{HotTicker, opts.StartDate, opts.StartDate.AddDate(0, 0, opts.Days*2)}
+	variants := []struct {
+		name  string
+		query string
+		args  []any
+	}{
+		{"legacy_self_join", legacySelfJoinQuery, openEnded},
+		{"trades_cte", repository.AggregatedTradesQuery, bounded},
+		{"daily_summaries", repository.AggregatedSummariesQuery, bounded},
+	}
+
+	for _, variant := range variants {
+		result, err := measure(ctx, pool, variant.name, variant.query, variant.args, opts)
+		if err != nil {
+			return nil, err
+		}
+		report.Variants = append(report.Variants, *result)
+	}
+
+	reference := report.Variants[0]
+	for i := range report.Variants {
+		report.Variants[i].Speedup = float64(reference.Median) / float64(report.Variants[i].Median)
+	}
+	for _, v := range report.Variants[1:] {
+		if v.MaxRangeValue != reference.MaxRangeValue || v.MaxDailyVolume != reference.MaxDailyVolume {
+			return report, fmt.Errorf("benchmark: resultado divergente em %s: (%.4f, %d), esperado (%.4f, %d)",
+				v.Name, v.MaxRangeValue, v.MaxDailyVolume, reference.MaxRangeValue, reference.MaxDailyVolume)
+		}
+	}
+	for _, v := range report.Variants[1:] {
+		if v.Speedup < opts.MinSpeedup {
+			return report, fmt.Errorf("benchmark: ganho insuficiente em %s: %.1fx, mínimo exigido %.1fx",
+				v.Name, v.Speedup, opts.MinSpeedup)
+		}
+	}
+
+	return report, nil
+}
+
+// createSchema recria o schema isolado e aplica nele as migrações embutidas, de modo
+// que o benchmark mede exatamente as tabelas, partições e índices usados pela API.
+// O search_path do pool aponta para o schema, então as migrações (e o registro em
+// 'schema_migrations') não tocam o schema da aplicação.
+func createSchema(ctx context.Context, pool *pgxpool.Pool) error {
+	statements := []string{
+		fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", Schema),
+		fmt.Sprintf("CREATE SCHEMA %s", Schema),
+	}
+	for _, stmt := range statements {
+		if _, err := pool.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("benchmark: falha ao preparar schema: %w", err)
+		}
+	}
+
+	migrator, err := migrate.NewEmbedded(pool)
+	if err != nil {
+		return fmt.Errorf("benchmark: %w", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		return fmt.Errorf("benchmark: falha ao aplicar migrações: %w", err)
+	}
+	return nil
+}
+
+// seed gera negociações sintéticas em dias úteis. O instrumento HotTicker recebe
+// HotFactor vezes mais negociações que os demais, reproduzindo a concentração de
+// liquidez do mercado real. As partições mensais são criadas antes da carga; em
+// seguida atualiza o mapa de visibilidade (necessário para index-only scans) e
+// calcula os resumos diários.
+func seed(ctx context.Context, pool *pgxpool.Pool, opts AggregatedOptions) (int64, error) {
+	partitions := `
+        SELECT ensure_trades_partition(m::DATE)
+        FROM generate_series(date_trunc('month', $1::DATE), $1::DATE + ($2 * 2), INTERVAL '1 month') m;
+    `
+	if _, err := pool.Exec(ctx, partitions, opts.StartDate, opts.Days); err != nil {
+		return 0, fmt.Errorf("benchmark: falha ao criar partições: %w", err)
+	}
+
+	insert := `
+        INSERT INTO trades (trade_date, instrument_code, negotiated_price, negotiated_quantity, closing_time)
+        SELECT
+            d.day,
+            CASE WHEN t = 1 THEN $5 ELSE 'TCK' || LPAD(t::TEXT, 4, '0') END,
+            ROUND((10 + t % 90 + random() * 5)::NUMERIC, 2),
+            (1 + FLOOR(random() * 10))::INT * 100,
+            TO_CHAR(TIME '10:00' + random() * INTERVAL '7 hours', 'HH24MISSMS')
+        FROM
+            (SELECT g::DATE AS day
+             FROM generate_series($1::DATE, $1::DATE + ($2 * 2), INTERVAL '1 day') g
+             WHERE EXTRACT(ISODOW FROM g) < 6
+             LIMIT $2) d
+        CROSS JOIN generate_series(1, $3) t
+        CROSS JOIN LATERAL generate_series(1, CASE WHEN t = 1 THEN $4 * $6 ELSE $4 END) n;
+    `
+	tag, err := pool.Exec(ctx, insert, opts.StartDate, opts.Days, opts.Tickers, opts.TradesPerDay, HotTicker, opts.HotFactor)
+	if err != nil {
+		return 0, fmt.Errorf("benchmark: falha ao popular trades: %w", err)
+	}
+
+	if _, err := pool.Exec(ctx, "VACUUM (ANALYZE) trades"); err != nil {
+		return 0, fmt.Errorf("benchmark: falha ao analisar trades: %w", err)
+	}
+
+	repo := repository.NewPostgresTradeRepository(pool)
+	dates, err := repo.ListTradeDates(ctx, time.Time{}, time.Time{}, false)
+	if err != nil {
+		return 0, fmt.Errorf("benchmark: %w", err)
+	}
+	for _, date := range dates {
+		if _, err := repo.RebuildDailySummaries(ctx, date); err != nil {
+			return 0, fmt.Errorf("benchmark: %w", err)
+		}
+	}
+	if _, err := pool.Exec(ctx, "VACUUM (ANALYZE) daily_summaries"); err != nil {
+		return 0, fmt.Errorf("benchmark: falha ao analisar daily_summaries: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// measure executa uma variante Iterations vezes (após um aquecimento), registra
+// mediana e melhor tempo e grava o plano de execução com EXPLAIN (ANALYZE, BUFFERS).
+func measure(ctx context.Context, pool *pgxpool.Pool, name, query string, args []any, opts AggregatedOptions) (*VariantResult, error) {
+	result := &VariantResult{Name: name}
+
+	// Aquecimento: carrega páginas no cache para medir a consulta, não o disco.
+	if err := pool.QueryRow(ctx, query, args...).Scan(&result.MaxRangeValue, &result.MaxDailyVolume); err != nil {
+		return nil, fmt.Errorf("benchmark: falha ao executar %s: %w", name, err)
+	}
+
+	durations := make([]time.Duration, 0, opts.Iterations)
+	for i := 0; i < opts.Iterations; i++ {
+		start := time.Now()
+		var maxRange float64
+		var maxVolume int64
+		if err := pool.QueryRow(ctx, query, args...).Scan(&maxRange, &maxVolume); err != nil {
+			return nil, fmt.Errorf("benchmark: falha ao executar %s: %w", name, err)
+		}
+		durations = append(durations, time.Since(start))
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	result.Best = durations[0]
+	result.Median = durations[len(durations)/2]
+
+	plan, err := explain(ctx, pool, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("benchmark: falha ao obter plano de %s: %w", name, err)
+	}
+	result.PlanFile = filepath.Join(opts.OutputDir, name+".plan.txt")
+	if err := os.WriteFile(result.PlanFile, []byte(plan), 0o644); err != nil {
+		return nil, fmt.Errorf("benchmark: falha ao gravar plano de %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+// explain retorna o plano de execução real da consulta em formato texto.
+func explain(ctx context.Context, pool *pgxpool.Pool, query string, args []any) (string, error) {
+	query = strings.TrimSuffix(strings.TrimSpace(query), ";")
+	rows, err := pool.Query(ctx, "EXPLAIN (ANALYZE, BUFFERS) "+query, args...)
+	if err != nil {
+		return "", err
+	}
+	lines, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
@@ -0,0 +1,206 @@
+// Package calendar implementa o calendário de pregões da B3: fins de semana,
+// feriados nacionais e de São Paulo e pregões com horário especial.
+package calendar
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// scopeSpecial identifica, no arquivo de feriados, um pregão com horário especial.
+const scopeSpecial = "especial"
+
+// bundled é o arquivo de feriados embutido nos binários.
+//
+//go:embed holidays.csv
+var bundled []byte
+
+// Calendar responde se uma data tem pregão. Datas são comparadas pelo dia, ignorando
+// horário e fuso. É imutável depois de carregado e seguro para uso concorrente.
+// Apenas os anos presentes no arquivo são cobertos; nos demais só os fins de semana
+// são conhecidos (ver Uncovered).
+type Calendar struct {
+	holidays map[time.Time]entity.Holiday
+	special  map[time.Time]entity.TradingDay
+	years    map[int]bool
+}
+
+// Default retorna o calendário embutido nos binários.
+func Default() (*Calendar, error) {
+	return Load(bytes.NewReader(bundled))
+}
+
+// MustDefault é como Default, mas entra em pânico se o arquivo embutido for inválido.
+func MustDefault() *Calendar {
+	c, err := Default()
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// LoadFile lê um arquivo de feriados no formato de holidays.csv. Sem caminho,
+// retorna o calendário embutido.
+func LoadFile(path string) (*Calendar, error) {
+	if path == "" {
+		return Default()
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("calendar: falha ao abrir arquivo de feriados: %w", err)
+	}
+	defer file.Close()
+	return Load(file)
+}
+
+// Load lê feriados e pregões especiais no formato "data;tipo;descrição[;abertura]".
+// Linhas vazias e iniciadas por '#' são ignoradas.
+func Load(r io.Reader) (*Calendar, error) {
+	c := &Calendar{
+		holidays: make(map[time.Time]entity.Holiday),
+		special:  make(map[time.Time]entity.TradingDay),
+		years:    make(map[int]bool),
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.Split(line, ";")
+		if len(parts) < 3 {
+			return nil, fmt.Errorf("calendar: linha %d inválida, esperado data;tipo;descrição", lineNumber)
+		}
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, fmt.Errorf("calendar: data inválida na linha %d: %w", lineNumber, err)
+		}
+		scope, description := strings.TrimSpace(parts[1]), strings.TrimSpace(parts[2])
+
+		switch scope {
+		case entity.HolidayScopeNational, entity.HolidayScopeSaoPaulo:
+			c.holidays[date] = entity.Holiday{Date: date, Scope: scope, Description: description}
+		case scopeSpecial:
+			day := entity.TradingDay{Date: date, Special: true, Description: description}
+			if len(parts) > 3 {
+				opensAt := strings.TrimSpace(parts[3])
+				if _, err := time.Parse("15:04", opensAt); err != nil {
+					return nil, fmt.Errorf("calendar: horário de abertura inválido na linha %d: %w", lineNumber, err)
+				}
+				day.OpensAt = opensAt
+			}
+			c.special[date] = day
+		default:
+			return nil, fmt.Errorf("calendar: tipo '%s' inválido na linha %d. Use nacional, sao_paulo ou especial", scope, lineNumber)
+		}
+		c.years[date.Year()] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("calendar: falha ao ler arquivo de feriados: %w", err)
+	}
+
+	return c, nil
+}
+
+// day normaliza uma data para a chave dos mapas (meia-noite UTC do mesmo dia).
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Years retorna os anos cobertos pelo arquivo de feriados, em ordem crescente.
+func (c *Calendar) Years() []int {
+	years := make([]int, 0, len(c.years))
+	for year := range c.years {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+	return years
+}
+
+// Covers indica se o ano da data está no arquivo de feriados.
+func (c *Calendar) Covers(t time.Time) bool {
+	return c.years[t.Year()]
+}
+
+// Uncovered retorna os anos do intervalo [from, to] ausentes do arquivo de feriados,
+// para os quais o calendário trata todo dia útil como pregão.
+func (c *Calendar) Uncovered(from, to time.Time) []int {
+	var years []int
+	for year := from.Year(); year <= to.Year(); year++ {
+		if !c.years[year] {
+			years = append(years, year)
+		}
+	}
+	return years
+}
+
+// IsTradingDay indica se há pregão na data.
+func (c *Calendar) IsTradingDay(t time.Time) bool {
+	d := day(t)
+	if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+		return false
+	}
+	_, holiday := c.holidays[d]
+	return !holiday
+}
+
+// PreviousTradingDay retorna o último pregão estritamente anterior à data.
+func (c *Calendar) PreviousTradingDay(t time.Time) time.Time {
+	d := day(t).AddDate(0, 0, -1)
+	for !c.IsTradingDay(d) {
+		d = d.AddDate(0, 0, -1)
+	}
+	return d
+}
+
+// LastTradingDays retorna o primeiro e o último dos n pregões que terminam em 'end'
+// (inclusivo). Se 'end' não tiver pregão, a janela termina no pregão anterior.
+func (c *Calendar) LastTradingDays(end time.Time, n int) (from, to time.Time) {
+	to = day(end)
+	if !c.IsTradingDay(to) {
+		to = c.PreviousTradingDay(to)
+	}
+	from = to
+	for i := 1; i < n; i++ {
+		from = c.PreviousTradingDay(from)
+	}
+	return from, to
+}
+
+// Range lista os pregões e os feriados em dias úteis no intervalo [from, to].
+func (c *Calendar) Range(from, to time.Time) entity.TradingCalendar {
+	result := entity.TradingCalendar{
+		From:        day(from),
+		To:          day(to),
+		TradingDays: []entity.TradingDay{},
+		Holidays:    []entity.Holiday{},
+	}
+	for d := result.From; !d.After(result.To); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			continue
+		}
+		if holiday, ok := c.holidays[d]; ok {
+			result.Holidays = append(result.Holidays, holiday)
+			continue
+		}
+		if special, ok := c.special[d]; ok {
+			result.TradingDays = append(result.TradingDays, special)
+			continue
+		}
+		result.TradingDays = append(result.TradingDays, entity.TradingDay{Date: d})
+	}
+	return result
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
 	"go.uber.org/zap"
@@ -22,7 +23,23 @@ type Config struct {
 	Mode        string `json:"mode"`
 	DatabaseURL string `json:"database_url"`
 	*PGSQLConfig
-	FilePath string `json:"file_path"`
+	FilePath       string `json:"file_path"`
+	B3HolidaysFile string `json:"b3_holidays_file"` // Arquivo de feriados da B3; vazio usa o calendário embutido
+	*IngestConfig
+}
+
+// IngestConfig agrupa os parâmetros de desempenho da CLI de ingestão.
+// Valores zerados indicam que o padrão do serviço deve ser usado.
+type IngestConfig struct {
+	INGEST_WORKERS         int     `json:"ingest_workers"`
+	INGEST_MODE            string  `json:"ingest_mode"`
+	INGEST_BATCH_SIZE      int     `json:"ingest_batch_size"`
+	INGEST_COMMIT_INTERVAL int     `json:"ingest_commit_interval"`
+	INGEST_DETECT_OUTLIERS bool    `json:"ingest_detect_outliers"` // Detecta outliers nas datas carregadas
+	INGEST_MAX_GAP_PCT     float64 `json:"ingest_max_gap_pct"`     // % máximo de negócios ausentes por data (0 apenas registra)
+	INGEST_VERIFY          bool    `json:"ingest_verify"`          // Reconcilia o arquivo com 'trades' após a carga
+
+	TRADES_RETENTION_MONTHS int `json:"trades_retention_months"` // Meses de partições mantidos pelo "partitions prune"
 }
 
 type PGSQLConfig struct {
@@ -93,9 +110,63 @@ func NewConfig() *Config {
 		conf.PGSQLConfig.SRV_DB_SSL_MODE = SRV_DB_SSL_MODE
 	}
 
+	conf.B3HolidaysFile = os.Getenv("B3_HOLIDAYS_FILE")
+
+	conf.INGEST_MODE = os.Getenv("INGEST_MODE")
+	conf.INGEST_WORKERS = getEnvInt("INGEST_WORKERS")
+	conf.INGEST_BATCH_SIZE = getEnvInt("INGEST_BATCH_SIZE")
+	conf.INGEST_COMMIT_INTERVAL = getEnvInt("INGEST_COMMIT_INTERVAL")
+	conf.INGEST_DETECT_OUTLIERS = getEnvBool("INGEST_DETECT_OUTLIERS")
+	conf.INGEST_MAX_GAP_PCT = getEnvFloat("INGEST_MAX_GAP_PCT")
+	conf.INGEST_VERIFY = getEnvBool("INGEST_VERIFY")
+	conf.TRADES_RETENTION_MONTHS = getEnvInt("TRADES_RETENTION_MONTHS")
+
 	return conf
 }
 
+// getEnvInt lê uma variável de ambiente inteira, retornando 0 se ausente ou inválida.
+func getEnvInt(key string) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		logger.Info("Valor inteiro inválido em variável de ambiente, ignorando", zap.String("key", key), zap.String("value", value))
+		return 0
+	}
+	return n
+}
+
+// getEnvFloat lê uma variável de ambiente decimal, retornando 0 se ausente ou inválida.
+func getEnvFloat(key string) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		logger.Info("Valor decimal inválido em variável de ambiente, ignorando", zap.String("key", key), zap.String("value", value))
+		return 0
+	}
+	return f
+}
+
+// getEnvBool lê uma variável de ambiente booleana (true/false, 1/0), retornando false se
+// ausente ou inválida.
+func getEnvBool(key string) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return false
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		logger.Info("Valor booleano inválido em variável de ambiente, ignorando", zap.String("key", key), zap.String("value", value))
+		return false
+	}
+	return b
+}
+
 func defaultConf() *Config {
 	default_conf := Config{
 		Port: "8080",
@@ -105,6 +176,7 @@ func defaultConf() *Config {
 			DB_DRIVE: "postgres",
 			DB_PORT:  "5432",
 		},
+		IngestConfig: &IngestConfig{},
 	}
 
 	return &default_conf
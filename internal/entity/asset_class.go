@@ -0,0 +1,83 @@
+package entity
+
+import "regexp"
+
+// DefaultPriceTick é a variação mínima de preço, em R$, do mercado à vista da B3. Vale
+// para ações, units, BDRs, frações, opções e instrumentos sem tick conhecido.
+const DefaultPriceTick = 0.01
+
+// AssetClassOther classifica os códigos que não seguem nenhum padrão conhecido.
+const AssetClassOther = "other"
+
+// AssetClassPattern associa uma classe de ativo ao padrão do código de negociação.
+type AssetClassPattern struct {
+	Name    string
+	Pattern string // Expressão regular compatível com Go e com o operador ~ do PostgreSQL
+}
+
+// AssetClassPatterns são as classes inferidas do código do instrumento, avaliadas em
+// ordem: o primeiro padrão que casar define a classe. Units, ETFs e FIIs compartilham
+// o sufixo 11 e por isso formam uma única classe.
+var AssetClassPatterns = []AssetClassPattern{
+	{Name: "fractional", Pattern: `^[A-Z0-9]{4}([3-8]|11)F$`},
+	{Name: "unit", Pattern: `^[A-Z0-9]{4}11$`},
+	{Name: "bdr", Pattern: `^[A-Z0-9]{4}3[1-9]$`},
+	{Name: "stock", Pattern: `^[A-Z0-9]{4}[3-8]$`},
+	{Name: "option", Pattern: `^[A-Z0-9]{4}[A-X][0-9]{1,3}[A-Z]?$`},
+	{Name: "future", Pattern: `^[A-Z0-9]{3}[FGHJKMNQUVXZ][0-9]{2}$`},
+}
+
+var assetClassRegexps = func() []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, len(AssetClassPatterns))
+	for i, p := range AssetClassPatterns {
+		compiled[i] = regexp.MustCompile(p.Pattern)
+	}
+	return compiled
+}()
+
+// futurePriceTicks são as variações mínimas de preço dos contratos futuros mais
+// negociados, pela mercadoria (três primeiros caracteres do código), na unidade de
+// cotação de cada contrato.
+var futurePriceTicks = map[string]float64{
+	"WIN": 5,     // Mini Ibovespa, em pontos
+	"IND": 5,     // Ibovespa, em pontos
+	"WDO": 0.5,   // Mini dólar, em R$ por US$ 1.000
+	"DOL": 0.5,   // Dólar, em R$ por US$ 1.000
+	"DI1": 0.001, // DI de um dia, em % ao ano
+	"BGI": 0.05,  // Boi gordo, em R$ por arroba
+	"CCM": 0.01,  // Milho, em R$ por saca
+}
+
+// PriceTickOf retorna a variação mínima de preço do instrumento: a do contrato, para
+// os futuros de futurePriceTicks, e DefaultPriceTick para os demais.
+func PriceTickOf(instrumentCode string) float64 {
+	if AssetClassOf(instrumentCode) == "future" {
+		if tick, ok := futurePriceTicks[instrumentCode[:3]]; ok {
+			return tick
+		}
+	}
+	return DefaultPriceTick
+}
+
+// AssetClassOf retorna a classe de ativo do código de negociação.
+func AssetClassOf(instrumentCode string) string {
+	for i, re := range assetClassRegexps {
+		if re.MatchString(instrumentCode) {
+			return AssetClassPatterns[i].Name
+		}
+	}
+	return AssetClassOther
+}
+
+// IsAssetClass indica se o nome é uma classe de ativo conhecida.
+func IsAssetClass(name string) bool {
+	if name == AssetClassOther {
+		return true
+	}
+	for _, p := range AssetClassPatterns {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
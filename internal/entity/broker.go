@@ -0,0 +1,80 @@
+package entity
+
+import "time"
+
+// Broker associa o código de participante da B3 ao nome da corretora.
+type Broker struct {
+	ParticipantCode int    `json:"participant"` // Código do participante na B3
+	Name            string `json:"name"`        // Nome da corretora
+}
+
+// BrokerPosition é o fluxo de um participante em um instrumento no período.
+// As participações são percentuais do volume comprado ou vendido por todos os participantes.
+type BrokerPosition struct {
+	ParticipantCode     int     `json:"participant"`           // Código do participante na B3
+	Name                string  `json:"name,omitempty"`        // Nome da corretora, se mapeado
+	BuyVolume           int64   `json:"buy_volume"`            // Quantidade comprada
+	SellVolume          int64   `json:"sell_volume"`           // Quantidade vendida
+	NetVolume           int64   `json:"net_volume"`            // Comprado - vendido
+	BuyFinancialVolume  float64 `json:"buy_financial_volume"`  // R$ comprados
+	SellFinancialVolume float64 `json:"sell_financial_volume"` // R$ vendidos
+	NetFinancialVolume  float64 `json:"net_financial_volume"`  // R$ comprados - R$ vendidos
+	BuyTrades           int64   `json:"buy_trades"`            // Negociações como comprador
+	SellTrades          int64   `json:"sell_trades"`           // Negociações como vendedor
+	BuyShare            float64 `json:"buy_share"`             // % do volume comprado no período
+	SellShare           float64 `json:"sell_share"`            // % do volume vendido no período
+}
+
+// BrokerFlow resume o fluxo por corretora de um instrumento no período. O HHI
+// (Herfindahl-Hirschman) soma os quadrados das participações percentuais: vai de perto
+// de 0 (fluxo pulverizado) a 10000 (um único participante).
+type BrokerFlow struct {
+	InstrumentCode  string           `json:"ticker"`           // Código do instrumento (ticker)
+	From            time.Time        `json:"from"`             // Primeiro dia do intervalo
+	To              time.Time        `json:"to"`               // Último dia do intervalo
+	Volume          int64            `json:"volume"`           // Quantidade negociada com participantes identificados
+	FinancialVolume float64          `json:"financial_volume"` // R$ negociados com participantes identificados
+	BuyHHI          float64          `json:"buy_hhi"`          // Concentração do fluxo comprador
+	SellHHI         float64          `json:"sell_hhi"`         // Concentração do fluxo vendedor
+	TopBuyers       []BrokerPosition `json:"top_buyers"`       // Maiores compradores por quantidade
+	TopSellers      []BrokerPosition `json:"top_sellers"`      // Maiores vendedores por quantidade
+	Brokers         []BrokerPosition `json:"brokers"`          // Todos os participantes, por posição líquida decrescente
+}
+
+// ParticipantPair é o fluxo entre um participante comprador e um vendedor em um
+// instrumento: uma célula não vazia da matriz comprador x vendedor.
+type ParticipantPair struct {
+	Buyer           int     `json:"buyer"`                 // Código do participante comprador
+	BuyerName       string  `json:"buyer_name,omitempty"`  // Nome da corretora compradora, se mapeado
+	Seller          int     `json:"seller"`                // Código do participante vendedor
+	SellerName      string  `json:"seller_name,omitempty"` // Nome da corretora vendedora, se mapeado
+	TradeCount      int64   `json:"trade_count"`           // Negociações entre o par
+	Volume          int64   `json:"volume"`                // Quantidade negociada entre o par
+	FinancialVolume float64 `json:"financial_volume"`      // R$ negociados entre o par
+	VolumeShare     float64 `json:"volume_share"`          // % da quantidade negociada no pregão
+	SelfTrade       bool    `json:"self_trade"`            // Comprador e vendedor são o mesmo participante
+}
+
+// SelfTrade é uma negociação em que comprador e vendedor são o mesmo participante,
+// possível negócio direto (cross) ou wash trade.
+type SelfTrade struct {
+	Time        time.Time `json:"time"`           // Horário da negociação no fuso da B3
+	Price       float64   `json:"price"`          // Preço da negociação
+	Quantity    int64     `json:"quantity"`       // Quantidade negociada
+	Participant int       `json:"participant"`    // Código do participante
+	Name        string    `json:"name,omitempty"` // Nome da corretora, se mapeado
+}
+
+// ParticipantPairReport é o relatório de vigilância de um instrumento em um pregão: a
+// matriz de pares comprador x vendedor (apenas pares que negociaram, por volume
+// decrescente) e as negociações marcadas como self-trade.
+type ParticipantPairReport struct {
+	InstrumentCode  string            `json:"ticker"`            // Código do instrumento (ticker)
+	TradeDate       time.Time         `json:"date"`              // Pregão
+	TradeCount      int64             `json:"trade_count"`       // Negociações com participantes identificados
+	Volume          int64             `json:"volume"`            // Quantidade com participantes identificados
+	SelfTradeCount  int64             `json:"self_trade_count"`  // Negociações marcadas como self-trade
+	SelfTradeVolume int64             `json:"self_trade_volume"` // Quantidade das negociações marcadas
+	Pairs           []ParticipantPair `json:"pairs"`             // Pares comprador x vendedor
+	SelfTrades      []SelfTrade       `json:"self_trades"`       // Negociações marcadas, em ordem de horário
+}
@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// Escopos de dias sem pregão no calendário da B3.
+const (
+	HolidayScopeNational = "nacional"  // Feriado nacional
+	HolidayScopeSaoPaulo = "sao_paulo" // Feriado de São Paulo ou dia sem pregão definido pela B3
+)
+
+// TradingDay é um dia com pregão na B3.
+type TradingDay struct {
+	Date        time.Time `json:"date"`                      // Data do pregão
+	Special     bool      `json:"special_session,omitempty"` // Pregão com horário especial
+	OpensAt     string    `json:"opens_at,omitempty"`        // Início das negociações em pregões especiais (HH:MM)
+	Description string    `json:"description,omitempty"`     // Motivo do horário especial
+}
+
+// Holiday é um dia útil sem pregão na B3.
+type Holiday struct {
+	Date        time.Time `json:"date"`        // Data sem pregão
+	Scope       string    `json:"scope"`       // nacional ou sao_paulo
+	Description string    `json:"description"` // Nome do feriado
+}
+
+// TradingCalendar lista os pregões e feriados de um intervalo de datas.
+type TradingCalendar struct {
+	From        time.Time    `json:"from"`         // Primeira data do intervalo
+	To          time.Time    `json:"to"`           // Última data do intervalo (inclusiva)
+	TradingDays []TradingDay `json:"trading_days"` // Dias com pregão, em ordem cronológica
+	Holidays    []Holiday    `json:"holidays"`     // Dias úteis sem pregão, em ordem cronológica
+}
@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// IndicatorPoint é o valor de um indicador técnico em uma barra. Values fica vazio
+// enquanto a barra não tem histórico suficiente para o indicador.
+type IndicatorPoint struct {
+	Time   time.Time          `json:"time"`             // Início da barra (data do pregão em barras diárias)
+	Close  float64            `json:"close"`            // Fechamento da barra
+	Values map[string]float64 `json:"values,omitempty"` // Saídas do indicador (ex: macd, signal, histogram)
+}
+
+// IndicatorSeries é a série de um indicador técnico calculado sobre as barras de um
+// instrumento.
+type IndicatorSeries struct {
+	InstrumentCode string           `json:"ticker"`            // Código do instrumento (ticker)
+	Name           string           `json:"name"`              // sma, ema, rsi, macd, bollinger ou atr
+	Periods        []int            `json:"periods"`           // Períodos usados, em barras
+	StdDev         float64          `json:"std_dev,omitempty"` // Multiplicador do desvio padrão (bollinger)
+	Interval       string           `json:"interval"`          // 1d ou a duração das barras intradiárias
+	From           time.Time        `json:"from"`              // Primeiro dia do intervalo
+	To             time.Time        `json:"to"`                // Último dia do intervalo
+	WarmupBars     int              `json:"warmup_bars"`       // Barras lidas antes de 'from' para aquecer o indicador
+	Points         []IndicatorPoint `json:"points"`            // Barras do intervalo, em ordem cronológica
+}
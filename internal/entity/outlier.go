@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+// Tipos de marcação da detecção de outliers.
+const (
+	OutlierBlockQuantity  = "block_quantity"  // Quantidade muito acima do tamanho típico dos negócios
+	OutlierBlockValue     = "block_value"     // Volume financeiro muito acima do valor típico dos negócios
+	OutlierPriceDeviation = "price_deviation" // Preço distante da média móvel dos negócios anteriores
+)
+
+// OutlierKinds lista os tipos de marcação aceitos.
+var OutlierKinds = []string{OutlierBlockQuantity, OutlierBlockValue, OutlierPriceDeviation}
+
+// TradeOutlier é uma negociação marcada pela detecção de outliers.
+type TradeOutlier struct {
+	InstrumentCode    string    `json:"ticker"`             // Código do instrumento (ticker)
+	Time              time.Time `json:"time"`               // Horário da negociação no fuso da B3
+	Price             float64   `json:"price"`              // Preço da negociação
+	Quantity          int64     `json:"quantity"`           // Quantidade negociada
+	BuyerParticipant  int       `json:"buyer_participant"`  // Participante comprador
+	SellerParticipant int       `json:"seller_participant"` // Participante vendedor
+	Session           int       `json:"session"`            // Código do tipo de sessão
+	Kind              string    `json:"kind"`               // Tipo da marcação (OutlierKinds)
+	Score             float64   `json:"score"`              // Múltiplo do tamanho típico ou desvio da média (%)
+	Reference         float64   `json:"reference"`          // Tamanho típico (ações ou R$) ou média móvel do preço
+	Sigma             *float64  `json:"sigma,omitempty"`    // Desvio em desvios padrão (price_deviation)
+}
+
+// OutlierReport lista as negociações marcadas em um pregão ou intervalo.
+type OutlierReport struct {
+	From     time.Time      `json:"from"`             // Primeiro dia do intervalo
+	To       time.Time      `json:"to"`               // Último dia do intervalo
+	Ticker   string         `json:"ticker,omitempty"` // Instrumento filtrado, se informado
+	Kind     string         `json:"kind,omitempty"`   // Tipo filtrado, se informado
+	Outliers []TradeOutlier `json:"outliers"`         // Marcações em ordem de data, instrumento e horário
+}
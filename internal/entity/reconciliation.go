@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"math"
+	"time"
+)
+
+// LoadTotals resume as negociações de um ticker em um pregão para a reconciliação de
+// cargas. NotionalTicks é a soma de preço x quantidade com o preço em unidades de
+// PriceScale (precisão de NUMERIC(18, 4)).
+type LoadTotals struct {
+	Rows          int64 `json:"rows"`           // Quantidade de negociações
+	Quantity      int64 `json:"quantity"`       // Soma das quantidades negociadas
+	NotionalTicks int64 `json:"notional_ticks"` // Soma de preço x PriceScale x quantidade
+}
+
+// Add acumula uma negociação nos totais.
+func (t *LoadTotals) Add(trade *Trade) {
+	t.Rows++
+	t.Quantity += int64(trade.NegotiatedQuantity)
+	t.NotionalTicks += int64(math.Round(trade.NegotiatedPrice*PriceScale)) * int64(trade.NegotiatedQuantity)
+}
+
+// LoadDiscrepancy é um ticker cujos totais no arquivo diferem dos gravados em 'trades'.
+// Um lado zerado indica ticker ausente no arquivo ou no banco.
+type LoadDiscrepancy struct {
+	TradeDate      time.Time  `json:"trade_date"` // Data do pregão
+	InstrumentCode string     `json:"ticker"`     // Código do instrumento (ticker)
+	File           LoadTotals `json:"file"`       // Totais lidos do arquivo
+	Stored         LoadTotals `json:"stored"`     // Totais gravados em 'trades'
+}
+
+// LoadVerification é o resultado da reconciliação de um arquivo com 'trades'. Linhas
+// rejeitadas pelo reader não entram nos totais por ticker e são contadas à parte.
+type LoadVerification struct {
+	File          string            `json:"file"`          // Arquivo relido
+	TradeDates    []time.Time       `json:"trade_dates"`   // Datas de pregão presentes no arquivo
+	Tickers       int               `json:"tickers"`       // Pares data x ticker comparados
+	Lines         int64             `json:"lines"`         // Linhas de dados do arquivo (sem o cabeçalho)
+	Rows          int64             `json:"rows"`          // Negociações lidas do arquivo
+	Rejected      int64             `json:"rejected"`      // Linhas que o reader não conseguiu interpretar
+	Discrepancies []LoadDiscrepancy `json:"discrepancies"` // Divergências em ordem de data e ticker
+}
+
+// Verified indica se a reconciliação não encontrou divergências nem linhas rejeitadas.
+func (v *LoadVerification) Verified() bool {
+	return len(v.Discrepancies) == 0 && v.Rejected == 0
+}
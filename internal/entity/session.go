@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"strconv"
+	"time"
+)
+
+// Tipos de sessão do pregão (TipoSessaoPregao no arquivo de negócios da B3).
+const (
+	SessionUnknown     = 0 // Sessão não informada (negociações carregadas sem a coluna)
+	SessionRegular     = 1 // Pregão regular
+	SessionAfterMarket = 6 // After-market
+)
+
+// SessionNames mapeia os tipos de sessão conhecidos para os nomes aceitos na API.
+var SessionNames = map[int]string{
+	SessionUnknown:     "unknown",
+	SessionRegular:     "regular",
+	SessionAfterMarket: "after_market",
+}
+
+// SessionName retorna o nome do tipo de sessão; códigos sem nome retornam o próprio número.
+func SessionName(session int) string {
+	if name, ok := SessionNames[session]; ok {
+		return name
+	}
+	return strconv.Itoa(session)
+}
+
+// ParseSession interpreta o nome (ex: regular, after_market) ou o código numérico de
+// um tipo de sessão.
+func ParseSession(value string) (int, bool) {
+	for session, name := range SessionNames {
+		if name == value {
+			return session, true
+		}
+	}
+	session, err := strconv.Atoi(value)
+	if err != nil || session < 0 || session > 99 {
+		return 0, false
+	}
+	return session, true
+}
+
+// SessionStats resume as negociações de um instrumento em um tipo de sessão.
+type SessionStats struct {
+	Session         int     `json:"session"`          // Código do tipo de sessão
+	Name            string  `json:"name"`             // Nome do tipo de sessão
+	Volume          int64   `json:"volume"`           // Quantidade negociada
+	FinancialVolume float64 `json:"financial_volume"` // Soma de preço * quantidade
+	TradeCount      int64   `json:"trade_count"`      // Número de negociações
+	High            float64 `json:"high"`             // Maior preço
+	Low             float64 `json:"low"`              // Menor preço
+	Range           float64 `json:"range"`            // Amplitude (máxima - mínima)
+	VWAP            float64 `json:"vwap"`             // Preço médio ponderado por volume
+	TradingDays     int64   `json:"trading_days"`     // Pregões com negociações na sessão
+	VolumeShare     float64 `json:"volume_share"`     // % da quantidade negociada no período
+}
+
+// SessionBreakdown é a divisão das negociações de um instrumento por tipo de sessão.
+type SessionBreakdown struct {
+	InstrumentCode string         `json:"ticker"`   // Código do instrumento (ticker)
+	From           time.Time      `json:"from"`     // Primeiro dia do intervalo
+	To             time.Time      `json:"to"`       // Último dia do intervalo
+	Sessions       []SessionStats `json:"sessions"` // Uma entrada por tipo de sessão, em ordem de código
+}
@@ -1,6 +1,19 @@
 package entity
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// PriceScale converte preços para inteiros com a precisão de NUMERIC(18, 4), evitando
+// erros de arredondamento de float64 nas somas (checksums, resumos diários e barras).
+const PriceScale = 10000
+
+// B3Location é o fuso horário dos horários publicados pela B3 (Brasília, sem horário de verão).
+var B3Location = time.FixedZone("BRT", -3*60*60)
 
 // Trade representa uma negociação de ativo na B3.
 type Trade struct {
@@ -9,11 +22,223 @@ type Trade struct {
 	NegotiatedPrice    float64   // Valor unitário do ativo. Considerar decimal.Decimal para precisão financeira se for crítico.
 	NegotiatedQuantity int       // Quantidade de ativos negociados
 	ClosingTime        string    // Formato "HHMMSSmmm"
+	BuyerParticipant   int       // Código do participante comprador (0 se não informado)
+	SellerParticipant  int       // Código do participante vendedor (0 se não informado)
+	SessionType        int       // Tipo de sessão do pregão (SessionRegular, SessionAfterMarket; 0 se não informado)
+	TradeID            int64     // Identificador do negócio, crescente por instrumento no pregão (0 se não informado)
+}
+
+// Timestamp combina TradeDate e ClosingTime em um instante no fuso da B3.
+func (t *Trade) Timestamp() (time.Time, error) {
+	return TradeTimestamp(t.TradeDate, t.ClosingTime)
+}
+
+// TradeTimestamp converte uma data de pregão e um horário "HHMMSSmmm" em um instante no fuso da B3.
+func TradeTimestamp(tradeDate time.Time, closingTime string) (time.Time, error) {
+	if len(closingTime) != 9 {
+		return time.Time{}, fmt.Errorf("horário de fechamento inválido '%s': esperado HHMMSSmmm", closingTime)
+	}
+	value, err := strconv.Atoi(closingTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("horário de fechamento inválido '%s': %w", closingTime, err)
+	}
+	hour, minute, second, millis := value/10000000, value/100000%100, value/1000%100, value%1000
+	return time.Date(tradeDate.Year(), tradeDate.Month(), tradeDate.Day(), hour, minute, second, millis*int(time.Millisecond), B3Location), nil
 }
 
 // AggregatedData representa a estrutura de dados agregados de saída da API.
+// As estatísticas completas só são preenchidas quando solicitadas (parâmetro 'fields'),
+// mantendo a resposta original para clientes existentes.
 type AggregatedData struct {
 	InstrumentCode string  `json:"ticker"`           // Código do instrumento (ticker)
 	MaxRangeValue  float64 `json:"max_range_value"`  // Maior preço unitário no período
 	MaxDailyVolume int     `json:"max_daily_volume"` // Volume máximo de negociações em um único dia
+	*AggregatedStatistics
+}
+
+// AggregatedStatistics complementa AggregatedData com as estatísticas completas do período.
+type AggregatedStatistics struct {
+	MinPrice         float64   `json:"min_price"`          // Menor preço unitário no período
+	FirstPrice       float64   `json:"first_price"`        // Abertura do primeiro pregão
+	LastPrice        float64   `json:"last_price"`         // Fechamento do último pregão
+	TotalVolume      int64     `json:"total_volume"`       // Soma das quantidades negociadas
+	FinancialVolume  float64   `json:"financial_volume"`   // Soma de preço * quantidade
+	TradeCount       int64     `json:"trade_count"`        // Número de negociações
+	AverageTradeSize float64   `json:"average_trade_size"` // Quantidade média por negociação
+	TradingDays      int64     `json:"trading_days"`       // Pregões com negociações
+	MaxRangeDate     time.Time `json:"max_range_date"`     // Pregão do maior preço (o mais antigo, em empate)
+	MaxVolumeDate    time.Time `json:"max_volume_date"`    // Pregão do maior volume (o mais antigo, em empate)
+}
+
+// AggregatedBatchItem é o resultado de um instrumento em uma consulta agregada em lote.
+// Instrumentos sem dados ou com parâmetros inválidos não interrompem o lote: são
+// marcados com Found=false e, no segundo caso, com a descrição do erro.
+type AggregatedBatchItem struct {
+	InstrumentCode string          `json:"ticker"`                // Código do instrumento (ticker)
+	From           *time.Time      `json:"data_inicio,omitempty"` // Início do intervalo consultado
+	To             *time.Time      `json:"data_fim,omitempty"`    // Fim do intervalo consultado (inclusivo)
+	Found          bool            `json:"found"`                 // Indica se há dados no intervalo
+	Data           *AggregatedData `json:"data,omitempty"`        // Dados agregados, quando encontrados
+	Error          string          `json:"error,omitempty"`       // Parâmetro inválido do item
+}
+
+// AggregatedStatisticsFields são os nomes JSON aceitos no parâmetro 'fields'.
+var AggregatedStatisticsFields = []string{
+	"min_price", "first_price", "last_price", "total_volume", "financial_volume", "trade_count",
+	"average_trade_size", "trading_days", "max_range_date", "max_volume_date",
+}
+
+// DailyOHLCV representa um pregão na série diária de um instrumento. Abertura e
+// fechamento são os preços da primeira e da última negociação pelo horário de fechamento.
+type DailyOHLCV struct {
+	TradeDate       time.Time `json:"trade_date"`       // Data do pregão
+	Open            float64   `json:"open"`             // Preço de abertura
+	High            float64   `json:"high"`             // Maior preço do dia
+	Low             float64   `json:"low"`              // Menor preço do dia
+	Close           float64   `json:"close"`            // Preço de fechamento
+	Volume          int64     `json:"volume"`           // Soma das quantidades negociadas
+	FinancialVolume float64   `json:"financial_volume"` // Soma de preço * quantidade
+	TradeCount      int64     `json:"trade_count"`      // Número de negociações
+}
+
+// DailySeries é a série diária OHLCV de um instrumento em um intervalo de datas.
+type DailySeries struct {
+	InstrumentCode string       `json:"ticker"` // Código do instrumento (ticker)
+	Days           []DailyOHLCV `json:"days"`   // Pregões em ordem cronológica
+}
+
+// PriceBenchmark reúne VWAP e TWAP de um instrumento em um intervalo de datas e,
+// opcionalmente, em uma janela intradiária. Preços são decimais exatos calculados
+// pelo PostgreSQL (NUMERIC) e serializados como números JSON sem passar por float64.
+type PriceBenchmark struct {
+	InstrumentCode  string      `json:"ticker"`               // Código do instrumento (ticker)
+	From            time.Time   `json:"from"`                 // Primeira data do intervalo
+	To              time.Time   `json:"to"`                   // Última data do intervalo (inclusiva)
+	StartTime       string      `json:"start_time,omitempty"` // Início da janela intradiária (HH:MM:SS)
+	EndTime         string      `json:"end_time,omitempty"`   // Fim da janela intradiária, exclusivo (HH:MM:SS)
+	VWAP            json.Number `json:"vwap"`                 // Preço médio ponderado por volume
+	TWAP            json.Number `json:"twap"`                 // Preço médio ponderado pelo tempo
+	Volume          int64       `json:"volume"`               // Quantidade total considerada
+	FinancialVolume json.Number `json:"financial_volume"`     // Soma exata de preço * quantidade
+	TradeCount      int64       `json:"trade_count"`          // Negociações consideradas
+	TradingDays     int64       `json:"trading_days"`         // Pregões com negociações no intervalo
+}
+
+// TradeChecksum resume um conjunto de negociações para validar cargas.
+// PriceTicks é a soma dos preços em décimos de milésimo (precisão de NUMERIC(18, 4)).
+type TradeChecksum struct {
+	Rows       int64 // Quantidade de negociações
+	Quantity   int64 // Soma das quantidades negociadas
+	PriceTicks int64 // Soma dos preços multiplicados por PriceScale
+}
+
+// Add acumula uma negociação no checksum.
+func (c *TradeChecksum) Add(t *Trade) {
+	c.Rows++
+	c.Quantity += int64(t.NegotiatedQuantity)
+	c.PriceTicks += int64(math.Round(t.NegotiatedPrice * PriceScale))
+}
+
+// Merge soma outro checksum a este.
+func (c *TradeChecksum) Merge(other TradeChecksum) {
+	c.Rows += other.Rows
+	c.Quantity += other.Quantity
+	c.PriceTicks += other.PriceTicks
+}
+
+// TradePartition descreve uma partição mensal da tabela 'trades'.
+type TradePartition struct {
+	Name          string    `json:"name"`           // Nome da partição, ex: trades_p202508
+	From          time.Time `json:"from"`           // Limite inferior (inclusivo) de trade_date
+	To            time.Time `json:"to"`             // Limite superior (exclusivo) de trade_date
+	EstimatedRows int64     `json:"estimated_rows"` // Estimativa de linhas segundo as estatísticas do PostgreSQL
+}
+
+// DailySummary representa o resumo diário (OHLCV) de um instrumento.
+type DailySummary struct {
+	InstrumentCode  string    `json:"ticker"`           // Código do instrumento (ticker)
+	TradeDate       time.Time `json:"trade_date"`       // Data do pregão
+	Open            float64   `json:"open"`             // Preço da primeira negociação do dia
+	High            float64   `json:"high"`             // Maior preço do dia
+	Low             float64   `json:"low"`              // Menor preço do dia
+	Close           float64   `json:"close"`            // Preço da última negociação do dia
+	Volume          int64     `json:"volume"`           // Soma das quantidades negociadas
+	FinancialVolume float64   `json:"financial_volume"` // Soma de preço * quantidade
+	TradeCount      int64     `json:"trade_count"`      // Número de negociações
+	VWAP            float64   `json:"vwap"`             // Preço médio ponderado por volume
+}
+
+// Bar é uma barra OHLCV construída a partir da sequência de negociações. Em barras de
+// tempo, Start e End delimitam o intervalo [Start, End); em barras por evento (negócios,
+// quantidade ou volume financeiro) são os horários da primeira e da última negociação.
+type Bar struct {
+	Start           time.Time `json:"start"`            // Início da barra
+	End             time.Time `json:"end"`              // Fim da barra
+	Open            float64   `json:"open"`             // Preço da primeira negociação
+	High            float64   `json:"high"`             // Maior preço
+	Low             float64   `json:"low"`              // Menor preço
+	Close           float64   `json:"close"`            // Preço da última negociação
+	Volume          int64     `json:"volume"`           // Soma das quantidades negociadas
+	FinancialVolume float64   `json:"financial_volume"` // Soma de preço * quantidade
+	TradeCount      int64     `json:"trade_count"`      // Número de negociações
+	VWAP            float64   `json:"vwap"`             // Preço médio ponderado por volume
+}
+
+// BarSeries é a sequência de barras de um instrumento em um pregão.
+type BarSeries struct {
+	InstrumentCode string    `json:"ticker"`              // Código do instrumento (ticker)
+	TradeDate      time.Time `json:"trade_date"`          // Data do pregão
+	Type           string    `json:"bar_type"`            // time, tick, volume ou dollar
+	Interval       string    `json:"interval,omitempty"`  // Duração das barras de tempo, ex: 5m
+	Threshold      float64   `json:"threshold,omitempty"` // Limite de fechamento das barras por evento
+	Bars           []Bar     `json:"bars"`                // Barras em ordem cronológica
+}
+
+// RankingEntry é a posição de um instrumento em um ranking de mercado.
+type RankingEntry struct {
+	Rank            int      `json:"rank"`                 // Posição no ranking (1 = primeiro)
+	InstrumentCode  string   `json:"ticker"`               // Código do instrumento (ticker)
+	AssetClass      string   `json:"asset_class"`          // Classe de ativo inferida do código
+	Value           float64  `json:"value"`                // Valor da métrica ordenada
+	Volume          int64    `json:"volume"`               // Soma das quantidades negociadas
+	FinancialVolume float64  `json:"financial_volume"`     // Soma de preço * quantidade
+	TradeCount      int64    `json:"trade_count"`          // Número de negociações
+	Close           float64  `json:"close"`                // Fechamento do último pregão do intervalo
+	ChangePct       *float64 `json:"change_pct,omitempty"` // Variação percentual no intervalo, se houver referência
+}
+
+// Ranking lista os instrumentos com maiores e menores valores de uma métrica.
+type Ranking struct {
+	Metric     string         `json:"metric"`                // volume, financial_volume, trades ou change_pct
+	From       time.Time      `json:"from"`                  // Primeiro pregão do intervalo
+	To         time.Time      `json:"to"`                    // Último pregão do intervalo (inclusivo)
+	AssetClass string         `json:"asset_class,omitempty"` // Filtro de classe de ativo aplicado
+	Top        []RankingEntry `json:"top"`                   // Maiores valores, em ordem decrescente
+	Bottom     []RankingEntry `json:"bottom"`                // Menores valores, em ordem crescente
+}
+
+// ScreenerRow são as métricas diárias de um instrumento retornadas pelo screener.
+type ScreenerRow struct {
+	InstrumentCode  string   `json:"ticker"`                 // Código do instrumento (ticker)
+	AssetClass      string   `json:"asset_class"`            // Classe de ativo inferida do código
+	Open            float64  `json:"open"`                   // Abertura do pregão
+	High            float64  `json:"high"`                   // Máxima do pregão
+	Low             float64  `json:"low"`                    // Mínima do pregão
+	Close           float64  `json:"close"`                  // Fechamento do pregão
+	VWAP            float64  `json:"vwap"`                   // Preço médio ponderado por volume
+	ChangePct       *float64 `json:"change_pct,omitempty"`   // Variação sobre o fechamento anterior (ou a abertura)
+	Volume          int64    `json:"volume"`                 // Quantidade negociada no pregão
+	VolumeAvg       *float64 `json:"volume_avg,omitempty"`   // Média de volume dos N pregões anteriores
+	VolumeRatio     *float64 `json:"volume_ratio,omitempty"` // Volume do pregão / média dos N anteriores
+	FinancialVolume float64  `json:"financial_volume"`       // Soma de preço * quantidade
+	TradeCount      int64    `json:"trade_count"`            // Número de negociações
+}
+
+// ScreenerResult é uma página do resultado do screener.
+type ScreenerResult struct {
+	Date     time.Time     `json:"date"`      // Pregão avaliado
+	Page     int           `json:"page"`      // Página retornada (a partir de 1)
+	PageSize int           `json:"page_size"` // Itens por página
+	Total    int64         `json:"total"`     // Total de instrumentos que atendem aos filtros
+	Results  []ScreenerRow `json:"results"`   // Instrumentos da página, na ordem solicitada
 }
@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// Tipos de falha na sequência de identificadores de negócio.
+const (
+	TradeIDGap        = "gap"        // Identificadores ausentes na sequência do instrumento
+	TradeIDDuplicate  = "duplicate"  // Identificador gravado em mais de uma linha
+	TradeIDMisaligned = "misaligned" // Identificadores fora do incremento da sequência
+)
+
+// TradeIDIssue é uma falha na sequência de CodigoIdentificadorNegocio de um instrumento
+// em um pregão.
+type TradeIDIssue struct {
+	TradeDate      time.Time `json:"trade_date"` // Data do pregão
+	InstrumentCode string    `json:"ticker"`     // Código do instrumento (ticker)
+	Kind           string    `json:"kind"`       // TradeIDGap, TradeIDDuplicate ou TradeIDMisaligned
+	FirstID        int64     `json:"first_id"`   // Primeiro identificador afetado
+	LastID         int64     `json:"last_id"`    // Último identificador afetado (= FirstID em duplicate)
+	Count          int64     `json:"count"`      // Negócios ausentes (gap), linhas extras (duplicate) ou fora do incremento (misaligned)
+}
+
+// TradeIDCheck resume a verificação de completude de um pregão.
+type TradeIDCheck struct {
+	TradeDate  time.Time      `json:"trade_date"`  // Data do pregão
+	Trades     int64          `json:"trades"`      // Negociações com identificador informado
+	Missing    int64          `json:"missing"`     // Identificadores ausentes
+	Duplicates int64          `json:"duplicates"`  // Linhas com identificador repetido
+	Misaligned int64          `json:"misaligned"`  // Linhas com identificador fora do incremento
+	MissingPct float64        `json:"missing_pct"` // Ausentes sobre os identificadores esperados (%)
+	Issues     []TradeIDIssue `json:"issues"`      // Falhas em ordem de instrumento e identificador
+}
+
+// Add acumula uma falha nos totais da verificação.
+func (c *TradeIDCheck) Add(issue TradeIDIssue) {
+	switch issue.Kind {
+	case TradeIDGap:
+		c.Missing += issue.Count
+	case TradeIDDuplicate:
+		c.Duplicates += issue.Count
+	case TradeIDMisaligned:
+		c.Misaligned += issue.Count
+	}
+	c.Issues = append(c.Issues, issue)
+}
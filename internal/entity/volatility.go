@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// IntradayVariance é a variância realizada de um pregão, calculada a partir dos
+// log-retornos entre barras intradiárias.
+type IntradayVariance struct {
+	TradeDate time.Time // Data do pregão
+	Variance  float64   // Soma dos quadrados dos log-retornos entre barras
+	Bars      int64     // Barras com negociações no pregão
+}
+
+// DailyReturn é um pregão da série de retornos e volatilidade. As volatilidades são
+// anualizadas e expressas em fração (0.25 = 25%), assim como retornos e drawdown.
+type DailyReturn struct {
+	TradeDate           time.Time `json:"trade_date"`             // Data do pregão
+	Close               float64   `json:"close"`                  // Preço de fechamento
+	LogReturn           *float64  `json:"log_return"`             // ln(fechamento / fechamento anterior); nulo no primeiro pregão
+	Parkinson           float64   `json:"parkinson"`              // Volatilidade de Parkinson do dia (máxima e mínima)
+	Intraday            *float64  `json:"intraday"`               // Volatilidade realizada das barras intradiárias; nula sem negociações
+	RollingCloseToClose *float64  `json:"rolling_close_to_close"` // Close-to-close nos últimos 'window' retornos
+	RollingParkinson    *float64  `json:"rolling_parkinson"`      // Parkinson nos últimos 'window' pregões
+	RollingIntraday     *float64  `json:"rolling_intraday"`       // Intradiária nos últimos 'window' pregões
+	Drawdown            float64   `json:"drawdown"`               // Queda desde o maior fechamento anterior (<= 0)
+	CumulativeReturn    float64   `json:"cumulative_return"`      // Retorno simples desde o primeiro fechamento
+}
+
+// VolatilityReport reúne retornos, volatilidades realizadas e drawdown de um
+// instrumento em um intervalo de datas.
+type VolatilityReport struct {
+	InstrumentCode   string        `json:"ticker"`                // Código do instrumento (ticker)
+	From             time.Time     `json:"from"`                  // Primeiro dia do intervalo
+	To               time.Time     `json:"to"`                    // Último dia do intervalo
+	Window           int           `json:"window"`                // Pregões das janelas móveis
+	BarMinutes       int           `json:"bar_minutes"`           // Duração das barras da volatilidade intradiária
+	TradingDays      int           `json:"trading_days"`          // Pregões com negociações
+	MeanLogReturn    *float64      `json:"mean_log_return"`       // Média dos log-retornos diários
+	CumulativeReturn float64       `json:"cumulative_return"`     // Retorno simples do primeiro ao último fechamento
+	CloseToClose     *float64      `json:"close_to_close"`        // Volatilidade close-to-close do período
+	Parkinson        float64       `json:"parkinson"`             // Volatilidade de Parkinson do período
+	Intraday         *float64      `json:"intraday"`              // Volatilidade realizada intradiária do período
+	MaxDrawdown      float64       `json:"max_drawdown"`          // Maior queda de um pico a um vale (<= 0)
+	PeakDate         *time.Time    `json:"peak_date,omitempty"`   // Pregão do pico do maior drawdown
+	TroughDate       *time.Time    `json:"trough_date,omitempty"` // Pregão do vale do maior drawdown
+	Days             []DailyReturn `json:"days"`                  // Pregões em ordem cronológica
+}
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// PriceLevel resume as negociações de um instrumento em um único preço.
+type PriceLevel struct {
+	Price           float64 // Preço negociado
+	Volume          int64   // Quantidade negociada no preço
+	FinancialVolume float64 // Soma de preço * quantidade
+	TradeCount      int64   // Número de negociações no preço
+}
+
+// VolumeProfileLevel é uma faixa de preço do perfil de volume, [PriceLow, PriceHigh).
+type VolumeProfileLevel struct {
+	PriceLow        float64 `json:"price_low"`        // Limite inferior da faixa (inclusivo)
+	PriceHigh       float64 `json:"price_high"`       // Limite superior da faixa (exclusivo)
+	Volume          int64   `json:"volume"`           // Quantidade negociada na faixa
+	FinancialVolume float64 `json:"financial_volume"` // Soma de preço * quantidade
+	TradeCount      int64   `json:"trade_count"`      // Número de negociações na faixa
+	VolumeShare     float64 `json:"volume_share"`     // % da quantidade negociada no período
+	InValueArea     bool    `json:"in_value_area"`    // Se a faixa pertence à área de valor
+}
+
+// VolumeProfile é o histograma de quantidade negociada por faixa de preço de um
+// instrumento, com o ponto de controle (POC, faixa de maior volume) e a área de valor
+// (faixas contíguas ao POC que concentram ValueAreaPct% do volume).
+type VolumeProfile struct {
+	InstrumentCode string               `json:"ticker"`          // Código do instrumento (ticker)
+	From           time.Time            `json:"from"`            // Primeiro dia do intervalo
+	To             time.Time            `json:"to"`              // Último dia do intervalo
+	BucketType     string               `json:"bucket_type"`     // tick ou percent
+	BucketSize     float64              `json:"bucket_size"`     // Tamanho pedido, em ticks ou %
+	TickSize       float64              `json:"tick_size"`       // Variação mínima de preço usada nas faixas
+	BucketWidth    float64              `json:"bucket_width"`    // Largura efetiva de cada faixa, em R$
+	Volume         int64                `json:"volume"`          // Quantidade total negociada
+	TradeCount     int64                `json:"trade_count"`     // Total de negociações
+	POC            float64              `json:"poc"`             // VWAP da faixa de maior volume
+	ValueAreaPct   float64              `json:"value_area_pct"`  // % do volume da área de valor
+	ValueAreaLow   float64              `json:"value_area_low"`  // Menor preço negociado na área de valor
+	ValueAreaHigh  float64              `json:"value_area_high"` // Maior preço negociado na área de valor
+	Levels         []VolumeProfileLevel `json:"levels"`          // Faixas com negociações, em ordem de preço
+}
@@ -3,6 +3,7 @@ package ingestion
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -14,9 +15,32 @@ import (
 	"go.uber.org/zap"
 )
 
+// errInvalidTradeID, errInvalidSessionType e errInvalidParticipant indicam um campo
+// opcional malformado (CodigoIdentificadorNegocio, TipoSessaoPregao ou código de
+// participante). A linha não é descartada:
+// parseTrade retorna a negociação com o campo em 0 (não informado) junto com um erro
+// que envolve o sentinela, e o reader apenas registra o aviso (ver isParseWarning).
+var (
+	errInvalidTradeID     = errors.New("CodigoIdentificadorNegocio inválido")
+	errInvalidSessionType = errors.New("TipoSessaoPregao inválido")
+	errInvalidParticipant = errors.New("código de participante inválido")
+)
+
+// isParseWarning indica se o erro de parseTrade é apenas um aviso sobre campos
+// opcionais, caso em que a negociação retornada deve ser mantida.
+func isParseWarning(err error) bool {
+	return errors.Is(err, errInvalidTradeID) || errors.Is(err, errInvalidSessionType) || errors.Is(err, errInvalidParticipant)
+}
+
+// headerPrefix identifica a linha de cabeçalho dos arquivos de negociações da B3.
+const headerPrefix = "DataReferencia;"
+
 // TradeReader define a interface para leitura de stream de negociações.
+// O canal de erros recebe no máximo um erro fatal de leitura e é fechado
+// antes do canal de negociações, permitindo ao consumidor distinguir um
+// arquivo lido por completo de uma leitura interrompida.
 type TradeReader interface {
-	Read(ctx context.Context, path string) <-chan entity.Trade
+	Read(ctx context.Context, path string) (<-chan entity.Trade, <-chan error)
 }
 
 // TradeStreamReader implementa TradeReader para arquivos de texto.
@@ -28,15 +52,21 @@ func NewTradeStreamReader() TradeReader {
 }
 
 // Read abre o arquivo em streaming, parseia cada linha em uma Trade
-// e envia para um canal. Erros de parsing são logados.
-func (c *TradeStreamReader) Read(ctx context.Context, path string) <-chan entity.Trade {
+// e envia para um canal. Erros de parsing são logados em errors.log (linhas com
+// identificador de negócio malformado são enviadas com TradeID 0 e logadas como
+// aviso); erros de leitura do arquivo são enviados ao canal de erros.
+func (c *TradeStreamReader) Read(ctx context.Context, path string) (<-chan entity.Trade, <-chan error) {
 	tradeCh := make(chan entity.Trade)
+	errCh := make(chan error, 1)
 
 	go func() {
 		defer close(tradeCh)
+		defer close(errCh) // Executado antes de close(tradeCh)
+
 		file, err := os.Open(path)
 		if err != nil {
 			logger.Error("erro ao abrir arquivo", err, zap.String("path", path))
+			errCh <- fmt.Errorf("ingestion: erro ao abrir arquivo: %w", err)
 			return
 		}
 		defer file.Close()
@@ -44,6 +74,7 @@ func (c *TradeStreamReader) Read(ctx context.Context, path string) <-chan entity
 		logFile, err := os.Create("errors.log")
 		if err != nil {
 			logger.Error("erro ao criar arquivo de log", err)
+			errCh <- fmt.Errorf("ingestion: erro ao criar arquivo de log: %w", err)
 			return
 		}
 		defer logFile.Close()
@@ -54,29 +85,67 @@ func (c *TradeStreamReader) Read(ctx context.Context, path string) <-chan entity
 			select {
 			case <-ctx.Done(): // Verifica se o contexto foi cancelado
 				logger.Info("pipeline cancelado pelo contexto")
+				errCh <- ctx.Err()
 				return
 			default:
 				line := scanner.Text()
 				trade, err := parseTrade(line)
-				if err != nil {
+				if isParseWarning(err) {
+					warning := strings.ReplaceAll(err.Error(), "\n", "; ")
+					logFile.WriteString(fmt.Sprintf("aviso: %s | linha: %s\n", warning, line))
+				} else if err != nil {
 					// Salva a linha com erro no log e continua o processamento
 					logFile.WriteString(fmt.Sprintf("erro: %v | linha: %s\n", err, line))
 					continue
 				}
-				tradeCh <- trade // Envia a trade parseada para o canal
+				select {
+				case tradeCh <- trade: // Envia a trade parseada para o canal
+				case <-ctx.Done():
+					logger.Info("pipeline cancelado pelo contexto")
+					errCh <- ctx.Err()
+					return
+				}
 			}
 		}
 
 		if err := scanner.Err(); err != nil {
 			logger.Error("erro ao ler arquivo", err, zap.String("path", path))
+			errCh <- fmt.Errorf("ingestion: erro ao ler arquivo: %w", err)
 		}
 	}()
 
-	return tradeCh
+	return tradeCh, errCh
+}
+
+// CountDataLines conta as linhas de dados de um arquivo de negociações, sem o cabeçalho e
+// sem linhas em branco. Comparada ao número de negociações enviadas por Read, indica
+// quantas linhas o reader rejeitou.
+func CountDataLines(path string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("ingestion: erro ao abrir arquivo: %w", err)
+	}
+	defer file.Close()
+
+	var lines int64
+	scanner := bufio.NewScanner(file)
+	for first := true; scanner.Scan(); first = false {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || (first && strings.HasPrefix(line, headerPrefix)) {
+			continue
+		}
+		lines++
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("ingestion: erro ao ler arquivo: %w", err)
+	}
+	return lines, nil
 }
 
 // parseTrade transforma uma linha do arquivo em uma struct Trade.
 // Ajustado para o formato exato das primeiras linhas do seu exemplo.
+// Campos opcionais malformados não invalidam a linha (ver errInvalidTradeID); os avisos
+// de todos eles são retornados juntos, com errors.Join.
 func parseTrade(line string) (entity.Trade, error) {
 	parts := strings.Split(line, ";")
 
@@ -107,13 +176,58 @@ func parseTrade(line string) (entity.Trade, error) {
 	// ClosingTime (HoraFechamento) está na posição 5
 	closingTime := parts[5]
 
+	// CodigoIdentificadorNegocio (posição 6); vazio ou malformado grava 0 (não
+	// informado). Os avisos de campos opcionais só são retornados no fim, junto com a
+	// negociação.
+	var tradeID int64
+	var warnings []error
+	if strings.TrimSpace(parts[6]) != "" {
+		if tradeID, err = strconv.ParseInt(strings.TrimSpace(parts[6]), 10, 64); err != nil || tradeID < 0 {
+			tradeID = 0
+			warnings = append(warnings, fmt.Errorf("%w '%s', gravado como 0", errInvalidTradeID, parts[6]))
+		}
+	}
+
+	// TipoSessaoPregao (posição 7); vazio ou malformado grava 0 (não informado)
+	sessionType, err := parseOptionalInt(parts[7])
+	if err != nil || sessionType < 0 {
+		sessionType = 0
+		warnings = append(warnings, fmt.Errorf("%w '%s', gravado como 0", errInvalidSessionType, parts[7]))
+	}
+
+	// CodigoParticipanteComprador e CodigoParticipanteVendedor (posições 9 e 10) são
+	// opcionais: arquivos sem essas colunas e códigos malformados gravam 0 (não informado).
+	var buyer, seller int
+	if len(parts) > 10 {
+		if buyer, err = parseOptionalInt(parts[9]); err != nil || buyer < 0 {
+			buyer = 0
+			warnings = append(warnings, fmt.Errorf("%w: CodigoParticipanteComprador '%s', gravado como 0", errInvalidParticipant, parts[9]))
+		}
+		if seller, err = parseOptionalInt(parts[10]); err != nil || seller < 0 {
+			seller = 0
+			warnings = append(warnings, fmt.Errorf("%w: CodigoParticipanteVendedor '%s', gravado como 0", errInvalidParticipant, parts[10]))
+		}
+	}
+
 	return entity.Trade{
 		TradeDate:          tradeDate,
 		InstrumentCode:     parts[1],
 		NegotiatedPrice:    negotiatedPrice,
 		NegotiatedQuantity: negotiatedQuantity,
 		ClosingTime:        closingTime,
-	}, nil
+		BuyerParticipant:   buyer,
+		SellerParticipant:  seller,
+		SessionType:        sessionType,
+		TradeID:            tradeID,
+	}, errors.Join(warnings...)
+}
+
+// parseOptionalInt interpreta um código opcional (participante, sessão); vazio equivale a 0 (não informado).
+func parseOptionalInt(s string) (int, error) {
+	if strings.TrimSpace(s) == "" {
+		return 0, nil
+	}
+	return parseInt(s)
 }
 
 func parseFloat(s string) (float64, error) {
@@ -0,0 +1,143 @@
+package ingestion
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTradeID(t *testing.T) {
+	const prefix = "2025-08-29;DI1F26;0;14,890;50;090000017;"
+	const suffix = ";1;2025-08-29;114;39"
+	tests := []struct {
+		name    string
+		id      string
+		want    int64
+		invalid bool
+	}{
+		{"informado", "30", 30, false},
+		{"vazio", "", 0, false},
+		{"com espaços", " 40 ", 40, false},
+		{"não numérico", "A10", 0, true},
+		{"negativo", "-10", 0, true},
+		{"acima de int64", "99999999999999999999", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trade, err := parseTrade(prefix + tt.id + suffix)
+			if tt.invalid != errors.Is(err, errInvalidTradeID) || (!tt.invalid && err != nil) {
+				t.Fatalf("parseTrade: erro %v, esperado identificador inválido: %v", err, tt.invalid)
+			}
+			if trade.TradeID != tt.want {
+				t.Errorf("TradeID = %d, esperado %d", trade.TradeID, tt.want)
+			}
+			// A linha é mantida mesmo com o identificador malformado.
+			if trade.InstrumentCode != "DI1F26" || trade.NegotiatedQuantity != 50 || trade.SessionType != 1 {
+				t.Errorf("negociação incompleta: %+v", trade)
+			}
+		})
+	}
+}
+
+func TestParseTradeParticipants(t *testing.T) {
+	const prefix = "2025-08-29;DI1F26;0;14,890;50;090000017;30;1;2025-08-29;"
+	tests := []struct {
+		name          string
+		participants  string
+		buyer, seller int
+		invalid       bool
+	}{
+		{"informados", "114;39", 114, 39, false},
+		{"vazios", ";", 0, 0, false},
+		{"comprador malformado", "X1;39", 0, 39, true},
+		{"vendedor negativo", "114;-39", 114, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trade, err := parseTrade(prefix + tt.participants)
+			if tt.invalid != errors.Is(err, errInvalidParticipant) || (!tt.invalid && err != nil) {
+				t.Fatalf("parseTrade: erro %v, esperado participante inválido: %v", err, tt.invalid)
+			}
+			if trade.BuyerParticipant != tt.buyer || trade.SellerParticipant != tt.seller {
+				t.Errorf("participantes = %d/%d, esperado %d/%d", trade.BuyerParticipant, trade.SellerParticipant, tt.buyer, tt.seller)
+			}
+			// A negociação é mantida: preço e quantidade entram nos agregados.
+			if trade.InstrumentCode != "DI1F26" || trade.NegotiatedQuantity != 50 || trade.TradeID != 30 {
+				t.Errorf("negociação incompleta: %+v", trade)
+			}
+		})
+	}
+
+	// Avisos de campos diferentes são retornados juntos.
+	_, err := parseTrade("2025-08-29;DI1F26;0;14,890;50;090000017;A10;1;2025-08-29;X1;39")
+	if !errors.Is(err, errInvalidTradeID) || !errors.Is(err, errInvalidParticipant) || !isParseWarning(err) {
+		t.Errorf("parseTrade: erro %v, esperados avisos de identificador e participante", err)
+	}
+}
+
+func TestParseTradeSessionType(t *testing.T) {
+	const prefix = "2025-08-29;DI1F26;0;14,890;50;090000017;30;"
+	const suffix = ";2025-08-29;114;39"
+	tests := []struct {
+		name    string
+		session string
+		want    int
+		invalid bool
+	}{
+		{"regular", "1", 1, false},
+		{"after-market", "6", 6, false},
+		{"vazio", "", 0, false},
+		{"não numérico", "R", 0, true},
+		{"negativo", "-1", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trade, err := parseTrade(prefix + tt.session + suffix)
+			if tt.invalid != errors.Is(err, errInvalidSessionType) || (!tt.invalid && err != nil) {
+				t.Fatalf("parseTrade: erro %v, esperado sessão inválida: %v", err, tt.invalid)
+			}
+			if tt.invalid && !isParseWarning(err) {
+				t.Errorf("sessão inválida deveria ser apenas um aviso: %v", err)
+			}
+			if trade.SessionType != tt.want {
+				t.Errorf("SessionType = %d, esperado %d", trade.SessionType, tt.want)
+			}
+			if trade.InstrumentCode != "DI1F26" || trade.NegotiatedQuantity != 50 || trade.BuyerParticipant != 114 {
+				t.Errorf("negociação incompleta: %+v", trade)
+			}
+		})
+	}
+}
+
+func TestCountDataLines(t *testing.T) {
+	const header = "DataReferencia;CodigoInstrumento;AcaoAtualizacao;PrecoNegocio;QuantidadeNegociada;HoraFechamento;CodigoIdentificadorNegocio;TipoSessaoPregao;DataNegocio;CodigoParticipanteComprador;CodigoParticipanteVendedor\n"
+	const line = "2025-08-29;DI1F26;0;14,890;50;090000017;30;1;2025-08-29;114;39\n"
+	tests := []struct {
+		name    string
+		content string
+		want    int64
+	}{
+		{"com cabeçalho", header + line + line, 2},
+		{"sem cabeçalho", line + line + line, 3},
+		{"linhas em branco", header + line + "\n  \n" + line, 2},
+		// Uma linha malformada é uma linha de dados: é o que o reader rejeita.
+		{"linha malformada", header + line + "2025-08-29;DI1F26\n", 2},
+		{"somente cabeçalho", header, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "trades.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("gravar arquivo: %v", err)
+			}
+			got, err := CountDataLines(path)
+			if err != nil {
+				t.Fatalf("CountDataLines: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CountDataLines = %d, esperado %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package ingestion
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+type summaryKey struct {
+	tradeDate      time.Time
+	instrumentCode string
+}
+
+// dailyAccumulator mantém o estado parcial do resumo de um instrumento em um dia.
+// Preços são guardados em décimos de milésimo (ticks de 0,0001).
+type dailyAccumulator struct {
+	openTime, closeTime   string
+	openTicks, closeTicks int64
+	highTicks, lowTicks   int64
+	volume                int64
+	financialTicks        int64 // Soma de preço (em ticks) * quantidade
+	count                 int64
+}
+
+// before indica se a negociação (closingTime, ticks) precede (otherTime, otherTicks)
+// na ordem usada para abertura e fechamento: horário e, em empate, preço.
+func before(closingTime string, ticks int64, otherTime string, otherTicks int64) bool {
+	if closingTime != otherTime {
+		return closingTime < otherTime
+	}
+	return ticks < otherTicks
+}
+
+// SummaryAccumulator calcula resumos diários (OHLCV, volume financeiro, VWAP)
+// por instrumento e data enquanto as negociações passam pelo pipeline.
+// Não é seguro para uso concorrente: cada worker mantém o seu e os resultados
+// são combinados com Merge.
+type SummaryAccumulator struct {
+	days map[summaryKey]*dailyAccumulator
+}
+
+// NewSummaryAccumulator cria um acumulador vazio.
+func NewSummaryAccumulator() *SummaryAccumulator {
+	return &SummaryAccumulator{days: make(map[summaryKey]*dailyAccumulator)}
+}
+
+// Add acumula uma negociação.
+func (a *SummaryAccumulator) Add(t *entity.Trade) {
+	ticks := int64(math.Round(t.NegotiatedPrice * entity.PriceScale))
+	quantity := int64(t.NegotiatedQuantity)
+	key := summaryKey{tradeDate: t.TradeDate, instrumentCode: t.InstrumentCode}
+
+	current := dailyAccumulator{
+		openTime: t.ClosingTime, closeTime: t.ClosingTime,
+		openTicks: ticks, closeTicks: ticks,
+		highTicks: ticks, lowTicks: ticks,
+		volume:         quantity,
+		financialTicks: ticks * quantity,
+		count:          1,
+	}
+
+	if day, ok := a.days[key]; ok {
+		day.merge(&current) // Mantém 'current' na pilha no caminho mais comum
+		return
+	}
+	created := current
+	a.days[key] = &created
+}
+
+// merge combina outro acumulador do mesmo instrumento e dia neste.
+func (d *dailyAccumulator) merge(other *dailyAccumulator) {
+	if before(other.openTime, other.openTicks, d.openTime, d.openTicks) {
+		d.openTime, d.openTicks = other.openTime, other.openTicks
+	}
+	if before(d.closeTime, d.closeTicks, other.closeTime, other.closeTicks) {
+		d.closeTime, d.closeTicks = other.closeTime, other.closeTicks
+	}
+	if other.highTicks > d.highTicks {
+		d.highTicks = other.highTicks
+	}
+	if other.lowTicks < d.lowTicks {
+		d.lowTicks = other.lowTicks
+	}
+	d.volume += other.volume
+	d.financialTicks += other.financialTicks
+	d.count += other.count
+}
+
+// Merge combina os resumos de outro acumulador neste.
+func (a *SummaryAccumulator) Merge(other *SummaryAccumulator) {
+	for key, day := range other.days {
+		if existing, ok := a.days[key]; ok {
+			existing.merge(day)
+			continue
+		}
+		copied := *day
+		a.days[key] = &copied
+	}
+}
+
+// vwap calcula o preço médio ponderado com 6 casas decimais, como ROUND(..., 6) em
+// daily_summaries.vwap: metade arredonda para longe de zero. A divisão é inteira, em
+// milionésimos, para que casos exatos de metade não dependam do erro do float64.
+func vwap(financialTicks, volume int64) float64 {
+	micros := (financialTicks*200 + volume) / (2 * volume)
+	return float64(micros) / (entity.PriceScale * 100)
+}
+
+// Summaries retorna os resumos acumulados, ordenados por data e instrumento.
+func (a *SummaryAccumulator) Summaries() []entity.DailySummary {
+	summaries := make([]entity.DailySummary, 0, len(a.days))
+	for key, day := range a.days {
+		summary := entity.DailySummary{
+			InstrumentCode:  key.instrumentCode,
+			TradeDate:       key.tradeDate,
+			Open:            float64(day.openTicks) / entity.PriceScale,
+			High:            float64(day.highTicks) / entity.PriceScale,
+			Low:             float64(day.lowTicks) / entity.PriceScale,
+			Close:           float64(day.closeTicks) / entity.PriceScale,
+			Volume:          day.volume,
+			FinancialVolume: float64(day.financialTicks) / entity.PriceScale,
+			TradeCount:      day.count,
+		}
+		if day.volume > 0 {
+			summary.VWAP = vwap(day.financialTicks, day.volume)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if !summaries[i].TradeDate.Equal(summaries[j].TradeDate) {
+			return summaries[i].TradeDate.Before(summaries[j].TradeDate)
+		}
+		return summaries[i].InstrumentCode < summaries[j].InstrumentCode
+	})
+	return summaries
+}
@@ -0,0 +1,230 @@
+package ingestion
+
+import (
+	"bufio"
+	"math/big"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// sampleTrade guarda uma negociação da amostra junto com o preço exato do arquivo.
+type sampleTrade struct {
+	trade entity.Trade
+	price *big.Rat
+}
+
+func loadSample(t *testing.T) []sampleTrade {
+	t.Helper()
+	file, err := os.Open("../../data/test_sample.txt")
+	if err != nil {
+		t.Fatalf("abrir amostra: %v", err)
+	}
+	defer file.Close()
+
+	var trades []sampleTrade
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // Cabeçalho
+	for scanner.Scan() {
+		line := scanner.Text()
+		trade, err := parseTrade(line)
+		if err != nil {
+			t.Fatalf("linha inválida na amostra %q: %v", line, err)
+		}
+		price, ok := new(big.Rat).SetString(strings.Replace(strings.Split(line, ";")[3], ",", ".", 1))
+		if !ok {
+			t.Fatalf("preço inválido na amostra %q", line)
+		}
+		trades = append(trades, sampleTrade{trade: trade, price: price})
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("ler amostra: %v", err)
+	}
+	return trades
+}
+
+// roundRat arredonda x para 'places' casas decimais com metade para longe de zero,
+// como ROUND(numeric, places) do PostgreSQL.
+func roundRat(x *big.Rat, places int) float64 {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(places)), nil)
+	scaled := new(big.Rat).Mul(x, new(big.Rat).SetInt(scale))
+	num, den := scaled.Num(), scaled.Denom()
+	// floor(scaled + 1/2) para valores não negativos.
+	twice := new(big.Int).Add(new(big.Int).Mul(num, big.NewInt(2)), den)
+	rounded := new(big.Int).Quo(twice, new(big.Int).Mul(den, big.NewInt(2)))
+	f, _ := new(big.Rat).SetFrac(rounded, scale).Float64()
+	return f
+}
+
+// directSummaries agrega as negociações como a consulta de RebuildDailySummaries: ordena
+// cada pregão por (closing_time, negotiated_price) e soma em aritmética decimal exata.
+func directSummaries(trades []sampleTrade) []entity.DailySummary {
+	type key struct {
+		date   time.Time
+		ticker string
+	}
+	groups := make(map[key][]sampleTrade)
+	for _, st := range trades {
+		k := key{st.trade.TradeDate, st.trade.InstrumentCode}
+		groups[k] = append(groups[k], st)
+	}
+
+	var summaries []entity.DailySummary
+	for k, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			if group[i].trade.ClosingTime != group[j].trade.ClosingTime {
+				return group[i].trade.ClosingTime < group[j].trade.ClosingTime
+			}
+			return group[i].price.Cmp(group[j].price) < 0
+		})
+		high, low := group[0].price, group[0].price
+		financial := new(big.Rat)
+		var volume int64
+		for _, st := range group {
+			if st.price.Cmp(high) > 0 {
+				high = st.price
+			}
+			if st.price.Cmp(low) < 0 {
+				low = st.price
+			}
+			quantity := int64(st.trade.NegotiatedQuantity)
+			financial.Add(financial, new(big.Rat).Mul(st.price, new(big.Rat).SetInt64(quantity)))
+			volume += quantity
+		}
+		toFloat := func(r *big.Rat) float64 { f, _ := r.Float64(); return f }
+		summaries = append(summaries, entity.DailySummary{
+			InstrumentCode:  k.ticker,
+			TradeDate:       k.date,
+			Open:            toFloat(group[0].price),
+			High:            toFloat(high),
+			Low:             toFloat(low),
+			Close:           toFloat(group[len(group)-1].price),
+			Volume:          volume,
+			FinancialVolume: toFloat(financial),
+			TradeCount:      int64(len(group)),
+			VWAP:            roundRat(new(big.Rat).Quo(financial, new(big.Rat).SetInt64(volume)), 6),
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].InstrumentCode < summaries[j].InstrumentCode })
+	return summaries
+}
+
+func TestSummaryAccumulatorMatchesDirectAggregation(t *testing.T) {
+	sample := loadSample(t)
+	want := directSummaries(sample)
+
+	// Distribuições dos negócios entre workers, como o pipeline faz ao ler do mesmo canal.
+	distributions := map[string]func(i, workers int) int{
+		"round-robin": func(i, workers int) int { return i % workers },
+		"blocos":      func(i, workers int) int { return i * workers / len(sample) },
+		"invertido":   func(i, workers int) int { return (len(sample) - 1 - i) % workers },
+	}
+
+	for name, assign := range distributions {
+		for _, workers := range []int{1, 2, 4, 7} {
+			accumulators := make([]*SummaryAccumulator, workers)
+			for w := range accumulators {
+				accumulators[w] = NewSummaryAccumulator()
+			}
+			for i := range sample {
+				accumulators[assign(i, workers)].Add(&sample[i].trade)
+			}
+
+			// O resultado não pode depender da ordem de combinação dos workers.
+			forward, backward := NewSummaryAccumulator(), NewSummaryAccumulator()
+			for w := 0; w < workers; w++ {
+				forward.Merge(accumulators[w])
+				backward.Merge(accumulators[workers-1-w])
+			}
+
+			for label, got := range map[string][]entity.DailySummary{"merge": forward.Summaries(), "merge invertido": backward.Summaries()} {
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s, %d workers, %s:\n got %+v\nwant %+v", name, workers, label, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestSummaryAccumulatorOpenCloseOrder(t *testing.T) {
+	date := time.Date(2025, 8, 29, 0, 0, 0, 0, time.UTC)
+	trade := func(closingTime string, price float64) entity.Trade {
+		return entity.Trade{TradeDate: date, InstrumentCode: "PETR4", ClosingTime: closingTime, NegotiatedPrice: price, NegotiatedQuantity: 100}
+	}
+
+	tests := []struct {
+		name        string
+		workers     [][]entity.Trade
+		open, close float64
+	}{
+		{
+			name:    "horário decide",
+			workers: [][]entity.Trade{{trade("100000000", 30), trade("090000000", 31)}, {trade("170000000", 29)}},
+			open:    31, close: 29,
+		},
+		{
+			name:    "empate no horário de abertura fica com o menor preço",
+			workers: [][]entity.Trade{{trade("100000000", 30.5)}, {trade("100000000", 30.1)}, {trade("110000000", 30.3)}},
+			open:    30.1, close: 30.3,
+		},
+		{
+			name:    "empate no horário de fechamento fica com o maior preço",
+			workers: [][]entity.Trade{{trade("100000000", 30)}, {trade("170000000", 30.4)}, {trade("170000000", 30.9), trade("170000000", 30.2)}},
+			open:    30, close: 30.9,
+		},
+		{
+			name:    "um único negócio é abertura e fechamento",
+			workers: [][]entity.Trade{{}, {trade("120000000", 28.75)}},
+			open:    28.75, close: 28.75,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := NewSummaryAccumulator()
+			for _, trades := range tt.workers {
+				worker := NewSummaryAccumulator()
+				for i := range trades {
+					worker.Add(&trades[i])
+				}
+				merged.Merge(worker)
+			}
+			summaries := merged.Summaries()
+			if len(summaries) != 1 {
+				t.Fatalf("%d resumos, esperado 1", len(summaries))
+			}
+			if summaries[0].Open != tt.open || summaries[0].Close != tt.close {
+				t.Errorf("abertura/fechamento = %v/%v, esperado %v/%v", summaries[0].Open, summaries[0].Close, tt.open, tt.close)
+			}
+		})
+	}
+}
+
+func TestVWAPRounding(t *testing.T) {
+	tests := []struct {
+		name           string
+		financialTicks int64
+		volume         int64
+		want           float64
+	}{
+		{"exato", 300000 * 100, 100, 30},
+		{"dízima arredonda para cima", 100001 + 2*100002, 3, 10.000167},
+		{"dízima arredonda para baixo", 2*100001 + 100002, 3, 10.000133},
+		// 0,5000 x 199 + 0,5005 x 1: VWAP exato 0,5000025. Em float64 a divisão dá
+		// 0,50000249999..., que arredondaria para 0,500002.
+		{"metade arredonda para longe de zero", 5000*199 + 5005, 200, 0.500003},
+		{"metade em preço alto", 1000011, 120, 0.833343},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := vwap(tt.financialTicks, tt.volume); got != tt.want {
+				t.Errorf("vwap(%d, %d) = %v, esperado %v", tt.financialTicks, tt.volume, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+// internal/migrate/migrate.go
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/migrations"
+)
+
+// ErrSchemaOutdated indica que o banco está em uma versão anterior à esperada pelo binário.
+var ErrSchemaOutdated = errors.New("schema do banco de dados desatualizado")
+
+// migrationLockKey identifica o advisory lock que impede execuções simultâneas do runner.
+const migrationLockKey = 0x42334d47 // "B3MG"
+
+// fileNameRe reconhece arquivos no formato NNN_descricao.up.sql / NNN_descricao.down.sql.
+var fileNameRe = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration representa uma versão do schema com seus scripts de aplicação e reversão.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status descreve uma migração conhecida pelo binário e se ela já foi aplicada.
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Migrator aplica e reverte migrações registrando o estado em 'schema_migrations'.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration // Ordenadas por versão
+}
+
+// New lê os scripts de migração de fsys e cria um Migrator.
+func New(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	loaded, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: loaded}, nil
+}
+
+// NewEmbedded cria um Migrator com as migrações embutidas no binário.
+func NewEmbedded(pool *pgxpool.Pool) (*Migrator, error) {
+	return New(pool, migrations.FS)
+}
+
+// load agrupa os arquivos .up.sql e .down.sql por versão.
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: falha ao listar migrações: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileNameRe.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: versão inválida em '%s': %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("migrate: falha ao ler '%s': %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migrate: versão %d possui nomes divergentes ('%s' e '%s')", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	loaded := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migrate: versão %d (%s) não possui script .up.sql", m.Version, m.Name)
+		}
+		loaded = append(loaded, *m)
+	}
+	sort.Slice(loaded, func(i, j int) bool { return loaded[i].Version < loaded[j].Version })
+
+	return loaded, nil
+}
+
+// LatestVersion retorna a maior versão embutida no binário.
+func (m *Migrator) LatestVersion() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// ensureTable cria a tabela de controle de versões, se necessário.
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	_, err := m.pool.Exec(ctx, `
+        CREATE TABLE IF NOT EXISTS schema_migrations (
+            version BIGINT PRIMARY KEY,
+            name TEXT NOT NULL,
+            applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+        )`)
+	if err != nil {
+		return fmt.Errorf("migrate: falha ao criar schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// applied retorna as versões registradas em 'schema_migrations' com a data de aplicação.
+func (m *Migrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	rows, err := m.pool.Query(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: falha ao ler schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("migrate: falha ao ler versão aplicada: %w", err)
+		}
+		versions[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("migrate: falha ao iterar schema_migrations: %w", err)
+	}
+	return versions, nil
+}
+
+// CurrentVersion retorna a maior versão aplicada, ou 0 se nenhuma foi aplicada.
+// Não cria 'schema_migrations', podendo ser usada por processos sem permissão de DDL.
+func (m *Migrator) CurrentVersion(ctx context.Context) (int64, error) {
+	var exists bool
+	if err := m.pool.QueryRow(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		return 0, fmt.Errorf("migrate: falha ao verificar schema_migrations: %w", err)
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	var version int64
+	if err := m.pool.QueryRow(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version); err != nil {
+		return 0, fmt.Errorf("migrate: falha ao obter versão atual: %w", err)
+	}
+	return version, nil
+}
+
+// Verify retorna ErrSchemaOutdated se o banco não estiver na versão mais recente
+// conhecida pelo binário. Um banco em versão mais nova é aceito.
+func (m *Migrator) Verify(ctx context.Context) error {
+	current, err := m.CurrentVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if latest := m.LatestVersion(); current < latest {
+		return fmt.Errorf("%w: versão atual %d, esperada %d (execute 'ingest migrate up')", ErrSchemaOutdated, current, latest)
+	}
+	return nil
+}
+
+// Status lista todas as migrações embutidas indicando quais já foram aplicadas.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Up aplica, em ordem, todas as migrações pendentes. Cada migração roda em sua
+// própria transação junto com o registro em 'schema_migrations'.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		ok, err := m.run(ctx, migration, true)
+		if err != nil {
+			return done, err
+		}
+		if ok {
+			done = append(done, migration)
+		}
+	}
+	return done, nil
+}
+
+// Down reverte as 'steps' migrações aplicadas mais recentes, da mais nova para a mais antiga.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == "" {
+			return done, fmt.Errorf("migrate: versão %d (%s) não possui script .down.sql", migration.Version, migration.Name)
+		}
+		if _, err := m.run(ctx, migration, false); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// run executa o script de subida ou descida de uma migração sob um advisory lock.
+// Retorna false quando a migração já estava no estado desejado (execução concorrente).
+func (m *Migrator) run(ctx context.Context, migration Migration, up bool) (bool, error) {
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("migrate: falha ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockKey); err != nil {
+		return false, fmt.Errorf("migrate: falha ao obter lock de migração: %w", err)
+	}
+
+	var applied bool
+	if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", migration.Version).Scan(&applied); err != nil {
+		return false, fmt.Errorf("migrate: falha ao consultar versão %d: %w", migration.Version, err)
+	}
+	if applied == up {
+		return false, nil
+	}
+
+	script, direction := migration.Up, "up"
+	if !up {
+		script, direction = migration.Down, "down"
+	}
+	if _, err := tx.Exec(ctx, script); err != nil {
+		return false, fmt.Errorf("migrate: falha ao executar %03d_%s.%s.sql: %w", migration.Version, migration.Name, direction, err)
+	}
+
+	if up {
+		_, err = tx.Exec(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name)
+	} else {
+		_, err = tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+	}
+	if err != nil {
+		return false, fmt.Errorf("migrate: falha ao registrar versão %d: %w", migration.Version, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("migrate: falha ao fazer commit da versão %d: %w", migration.Version, err)
+	}
+	return true, nil
+}
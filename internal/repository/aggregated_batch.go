@@ -0,0 +1,230 @@
+// internal/repository/aggregated_batch.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// AggregatedQuery é um item de GetAggregatedDataBatch: um instrumento e seu intervalo [From, To].
+type AggregatedQuery struct {
+	InstrumentCode string
+	From, To       time.Time
+}
+
+// aggregatedBatchRequests expande os arrays de parâmetros em uma linha por item,
+// numerada pela posição (ord, a partir de 1) na lista recebida.
+const aggregatedBatchRequests = `
+        WITH requests AS (
+            SELECT
+                r.instrument_code, r.start_date, r.end_date, r.ord
+            FROM
+                unnest($1::TEXT[], $2::DATE[], $3::DATE[]) WITH ORDINALITY AS r(instrument_code, start_date, end_date, ord)
+        )`
+
+// aggregatedBatchSummariesQuery calcula os agregados de todos os itens em uma única
+// consulta sobre daily_summaries. Itens sem resumos não retornam linha.
+const aggregatedBatchSummariesQuery = aggregatedBatchRequests + `
+        SELECT
+            r.ord,
+            MAX(s.high_price),
+            MAX(s.volume)
+        FROM
+            requests r
+        JOIN
+            daily_summaries s ON s.instrument_code = r.instrument_code
+                AND s.trade_date >= r.start_date AND s.trade_date <= r.end_date
+        GROUP BY
+            r.ord;
+    `
+
+// aggregatedBatchMissingQuery lista, por item (ord), os pregões do intervalo com
+// negociações em 'trades' e sem resumo em daily_summaries, com as mesmas consultas
+// pontuais aos índices de unsummarizedDatesQuery.
+const aggregatedBatchMissingQuery = aggregatedBatchRequests + `
+        SELECT
+            r.ord,
+            d::DATE
+        FROM
+            requests r, generate_series(r.start_date, r.end_date, INTERVAL '1 day') AS d
+        WHERE
+            EXISTS (SELECT 1 FROM trades t WHERE t.instrument_code = r.instrument_code AND t.trade_date = d::DATE)
+            AND NOT EXISTS (SELECT 1 FROM daily_summaries s WHERE s.instrument_code = r.instrument_code AND s.trade_date = d::DATE);
+    `
+
+// aggregatedBatchMergedQuery combina, por item, os pregões de daily_summaries com os
+// pregões sem resumo calculados diretamente de 'trades' (pares posição do item $4 e data $5).
+const aggregatedBatchMergedQuery = aggregatedBatchRequests + `,
+        daily AS (
+            SELECT
+                r.ord,
+                s.high_price AS max_price,
+                s.volume AS total_volume
+            FROM
+                requests r
+            JOIN
+                daily_summaries s ON s.instrument_code = r.instrument_code
+                    AND s.trade_date >= r.start_date AND s.trade_date <= r.end_date
+            UNION ALL
+            SELECT
+                r.ord,
+                MAX(t.negotiated_price),
+                SUM(t.negotiated_quantity)
+            FROM
+                unnest($4::BIGINT[], $5::DATE[]) AS m(ord, trade_date)
+            JOIN
+                requests r ON r.ord = m.ord
+            JOIN
+                trades t ON t.instrument_code = r.instrument_code AND t.trade_date = m.trade_date
+            GROUP BY
+                r.ord, t.trade_date
+        )
+        SELECT
+            ord,
+            MAX(max_price),
+            MAX(total_volume)
+        FROM
+            daily
+        GROUP BY
+            ord;
+    `
+
+// aggregatedBatchTradesQuery calcula os agregados diretamente de 'trades', com a mesma
+// forma de AggregatedTradesQuery, quando a consulta é restrita ao tipo de sessão $4.
+const aggregatedBatchTradesQuery = aggregatedBatchRequests + `,
+        daily AS (
+            SELECT
+                r.ord,
+                t.trade_date,
+                MAX(t.negotiated_price) AS max_price,
+                SUM(t.negotiated_quantity) AS total_volume
+            FROM
+                requests r
+            JOIN
+                trades t ON t.instrument_code = r.instrument_code
+                    AND t.trade_date >= r.start_date AND t.trade_date <= r.end_date
+            WHERE
+                $4::SMALLINT IS NULL OR t.session_type = $4
+            GROUP BY
+                r.ord, t.trade_date
+        )
+        SELECT
+            ord,
+            MAX(max_price),
+            MAX(total_volume)
+        FROM
+            daily
+        GROUP BY
+            ord;
+    `
+
+// GetAggregatedDataBatch calcula os agregados de vários instrumentos com consultas
+// baseadas em conjunto sobre daily_summaries. Os pregões sem resumo de cada item são
+// calculados de 'trades' e combinados aos demais na mesma consulta. Com 'session', todos
+// os itens são calculados de 'trades' apenas com as negociações daquele tipo de sessão.
+// O resultado segue a ordem de 'queries'; itens sem dados ficam nil.
+func (r *postgresTradeRepository) GetAggregatedDataBatch(ctx context.Context, queries []AggregatedQuery, session *int) ([]*entity.AggregatedData, error) {
+	results := make([]*entity.AggregatedData, len(queries))
+	if len(queries) == 0 {
+		return results, nil
+	}
+
+	if session != nil {
+		if err := r.queryAggregatedBatch(ctx, aggregatedBatchTradesQuery, queries, results, allIndexes(len(queries)), session); err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	indexes := allIndexes(len(queries))
+	ords, dates, err := r.unsummarizedBatchDates(ctx, queries, indexes)
+	if err != nil {
+		return nil, err
+	}
+	if len(ords) == 0 {
+		err = r.queryAggregatedBatch(ctx, aggregatedBatchSummariesQuery, queries, results, indexes)
+	} else {
+		err = r.queryAggregatedBatch(ctx, aggregatedBatchMergedQuery, queries, results, indexes, ords, dates)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// unsummarizedBatchDates retorna, em pares (ord, data), os pregões sem resumo dos itens
+// de 'queries' nas posições 'indexes'; ord é a posição (a partir de 1) em 'indexes'.
+func (r *postgresTradeRepository) unsummarizedBatchDates(ctx context.Context, queries []AggregatedQuery, indexes []int) ([]int64, []time.Time, error) {
+	rows, err := r.pool.Query(ctx, aggregatedBatchMissingQuery, batchArgs(queries, indexes)...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("repository: falha ao verificar datas sem resumo em lote: %w", err)
+	}
+	defer rows.Close()
+
+	var ords []int64
+	var dates []time.Time
+	for rows.Next() {
+		var ord int64
+		var date time.Time
+		if err := rows.Scan(&ord, &date); err != nil {
+			return nil, nil, fmt.Errorf("repository: falha ao ler data sem resumo em lote: %w", err)
+		}
+		ords = append(ords, ord)
+		dates = append(dates, date)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("repository: falha ao iterar datas sem resumo em lote: %w", err)
+	}
+	return ords, dates, nil
+}
+
+// batchArgs monta os arrays de instrumentos e intervalos ($1, $2, $3) dos itens de
+// 'queries' nas posições 'indexes'.
+func batchArgs(queries []AggregatedQuery, indexes []int) []any {
+	codes := make([]string, len(indexes))
+	starts := make([]time.Time, len(indexes))
+	ends := make([]time.Time, len(indexes))
+	for i, index := range indexes {
+		codes[i], starts[i], ends[i] = queries[index].InstrumentCode, queries[index].From, queries[index].To
+	}
+	return []any{codes, starts, ends}
+}
+
+// queryAggregatedBatch executa a consulta para os itens de 'queries' nas posições
+// 'indexes' e grava os resultados nas mesmas posições de 'results'. 'extra' são os
+// parâmetros seguintes aos arrays (ex: a sessão de aggregatedBatchTradesQuery).
+func (r *postgresTradeRepository) queryAggregatedBatch(ctx context.Context, query string, queries []AggregatedQuery, results []*entity.AggregatedData, indexes []int, extra ...any) error {
+	rows, err := r.pool.Query(ctx, query, append(batchArgs(queries, indexes), extra...)...)
+	if err != nil {
+		return fmt.Errorf("repository: falha ao buscar dados agregados em lote: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ord int64
+		var result entity.AggregatedData
+		if err := rows.Scan(&ord, &result.MaxRangeValue, &result.MaxDailyVolume); err != nil {
+			return fmt.Errorf("repository: falha ao ler dados agregados em lote: %w", err)
+		}
+		index := indexes[ord-1]
+		result.InstrumentCode = queries[index].InstrumentCode
+		results[index] = &result
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("repository: falha ao iterar dados agregados em lote: %w", err)
+	}
+	return nil
+}
+
+// allIndexes retorna as posições 0..n-1.
+func allIndexes(n int) []int {
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return indexes
+}
@@ -0,0 +1,109 @@
+// internal/repository/aggregated_statistics.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// aggregatedStatisticsSelect agrega uma linha por pregão (colunas trade_date, open_price,
+// high_price, low_price, close_price, volume, financial_volume, trade_count) nas
+// estatísticas completas do período. Empates em máximo ficam com o pregão mais antigo.
+const aggregatedStatisticsSelect = `
+        SELECT
+            MAX(high_price),
+            MAX(volume),
+            MIN(low_price),
+            (ARRAY_AGG(open_price ORDER BY trade_date))[1],
+            (ARRAY_AGG(close_price ORDER BY trade_date DESC))[1],
+            SUM(volume),
+            SUM(financial_volume),
+            SUM(trade_count),
+            COUNT(*),
+            (ARRAY_AGG(trade_date ORDER BY high_price DESC, trade_date))[1],
+            (ARRAY_AGG(trade_date ORDER BY volume DESC, trade_date))[1]
+        FROM
+            daily
+        HAVING
+            COUNT(*) > 0;
+    `
+
+// aggregatedStatisticsSummariesQuery lê os pregões de daily_summaries.
+const aggregatedStatisticsSummariesQuery = `
+        WITH daily AS (
+            SELECT
+                trade_date, open_price, high_price, low_price, close_price,
+                volume, financial_volume, trade_count
+            FROM
+                daily_summaries
+            WHERE
+                instrument_code = $1 AND trade_date >= $2 AND trade_date <= $3
+        )` + aggregatedStatisticsSelect
+
+// aggregatedStatisticsMergedQuery lê os pregões resumidos de daily_summaries e calcula
+// as datas $4 sem resumo diretamente de 'trades'.
+var aggregatedStatisticsMergedQuery = summarizedDailyCTE + aggregatedStatisticsSelect
+
+// aggregatedStatisticsSessionQuery calcula os pregões apenas com as negociações do tipo de sessão $4.
+var aggregatedStatisticsSessionQuery = sessionDailyCTE + aggregatedStatisticsSelect
+
+// GetAggregatedStatistics obtém os dados agregados do instrumento no intervalo
+// [startDate, endDate] com o bloco completo de estatísticas. Assim como GetAggregatedData, lê daily_summaries,
+// calcula de 'trades' os pregões sem resumo e usa apenas 'trades' quando 'session' é informada.
+func (r *postgresTradeRepository) GetAggregatedStatistics(ctx context.Context, instrumentCode string, startDate, endDate time.Time, session *int) (*entity.AggregatedData, error) {
+	var result *entity.AggregatedData
+	var err error
+	if session != nil {
+		result, err = r.queryAggregatedStatistics(ctx, aggregatedStatisticsSessionQuery, instrumentCode, startDate, endDate, *session)
+	} else {
+		var missing []time.Time
+		missing, err = r.unsummarizedDates(ctx, instrumentCode, startDate, endDate)
+		if err != nil {
+			return nil, err
+		}
+		if len(missing) == 0 {
+			result, err = r.queryAggregatedStatistics(ctx, aggregatedStatisticsSummariesQuery, instrumentCode, startDate, endDate)
+		} else {
+			result, err = r.queryAggregatedStatistics(ctx, aggregatedStatisticsMergedQuery, instrumentCode, startDate, endDate, missing)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("repository: dados não encontrados")
+		}
+		return nil, fmt.Errorf("repository: falha ao buscar estatísticas agregadas: %w", err)
+	}
+	return result, nil
+}
+
+func (r *postgresTradeRepository) queryAggregatedStatistics(ctx context.Context, query, instrumentCode string, startDate, endDate time.Time, extra ...any) (*entity.AggregatedData, error) {
+	stats := &entity.AggregatedStatistics{}
+	result := entity.AggregatedData{InstrumentCode: instrumentCode, AggregatedStatistics: stats}
+	args := append([]any{instrumentCode, startDate, endDate}, extra...)
+	err := r.pool.QueryRow(ctx, query, args...).Scan(
+		&result.MaxRangeValue,
+		&result.MaxDailyVolume,
+		&stats.MinPrice,
+		&stats.FirstPrice,
+		&stats.LastPrice,
+		&stats.TotalVolume,
+		&stats.FinancialVolume,
+		&stats.TradeCount,
+		&stats.TradingDays,
+		&stats.MaxRangeDate,
+		&stats.MaxVolumeDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if stats.TradeCount > 0 {
+		stats.AverageTradeSize = float64(stats.TotalVolume) / float64(stats.TradeCount)
+	}
+	return &result, nil
+}
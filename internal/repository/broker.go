@@ -0,0 +1,74 @@
+// internal/repository/broker.go
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// BrokerRepository mantém o mapeamento de códigos de participante para corretoras.
+type BrokerRepository interface {
+	UpsertBrokers(ctx context.Context, brokers []entity.Broker) (int64, error)
+	ListBrokers(ctx context.Context) ([]entity.Broker, error)
+}
+
+type postgresBrokerRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresBrokerRepository cria uma nova instância de postgresBrokerRepository.
+func NewPostgresBrokerRepository(pool *pgxpool.Pool) BrokerRepository {
+	return &postgresBrokerRepository{pool: pool}
+}
+
+// UpsertBrokers grava os nomes informados, substituindo os de códigos já existentes.
+// Códigos ausentes da lista são mantidos.
+func (r *postgresBrokerRepository) UpsertBrokers(ctx context.Context, brokers []entity.Broker) (int64, error) {
+	if len(brokers) == 0 {
+		return 0, nil
+	}
+
+	codes := make([]int32, len(brokers))
+	names := make([]string, len(brokers))
+	for i, b := range brokers {
+		codes[i], names[i] = int32(b.ParticipantCode), b.Name
+	}
+
+	query := `
+        INSERT INTO brokers (participant_code, name)
+        SELECT * FROM unnest($1::INTEGER[], $2::TEXT[])
+        ON CONFLICT (participant_code) DO UPDATE
+        SET name = EXCLUDED.name, updated_at = NOW();
+    `
+	tag, err := r.pool.Exec(ctx, query, codes, names)
+	if err != nil {
+		return 0, fmt.Errorf("repository: falha ao gravar corretoras: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ListBrokers lista o mapeamento de corretoras em ordem de código.
+func (r *postgresBrokerRepository) ListBrokers(ctx context.Context) ([]entity.Broker, error) {
+	rows, err := r.pool.Query(ctx, "SELECT participant_code, name FROM brokers ORDER BY participant_code")
+	if err != nil {
+		return nil, fmt.Errorf("repository: falha ao listar corretoras: %w", err)
+	}
+	defer rows.Close()
+
+	brokers := []entity.Broker{}
+	for rows.Next() {
+		var b entity.Broker
+		if err := rows.Scan(&b.ParticipantCode, &b.Name); err != nil {
+			return nil, fmt.Errorf("repository: falha ao ler corretora: %w", err)
+		}
+		brokers = append(brokers, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: falha ao iterar corretoras: %w", err)
+	}
+	return brokers, nil
+}
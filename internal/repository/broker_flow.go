@@ -0,0 +1,104 @@
+// internal/repository/broker_flow.go
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// GetBrokerFlows soma, por participante, as compras e vendas das negociações que
+// atendem ao filtro, com o nome da corretora quando mapeado em 'brokers'. Negociações
+// sem participante informado (código 0) são ignoradas do respectivo lado. Participações
+// e posições líquidas ficam a cargo do chamador.
+func (r *postgresTradeRepository) GetBrokerFlows(ctx context.Context, q TradeQuery) ([]entity.BrokerPosition, error) {
+	query := `
+        WITH window_trades AS (
+            SELECT
+                buyer_participant,
+                seller_participant,
+                negotiated_quantity,
+                negotiated_price * negotiated_quantity AS financial
+            FROM
+                trades
+            WHERE
+                instrument_code = $1
+                AND trade_date >= $2 AND trade_date <= $3
+                AND ($4::TEXT IS NULL OR closing_time >= $4)
+                AND ($5::TEXT IS NULL OR closing_time < $5)
+                AND ($6::SMALLINT IS NULL OR session_type = $6)
+        ),
+        flows AS (
+            SELECT
+                buyer_participant AS participant,
+                SUM(negotiated_quantity) AS buy_volume,
+                SUM(financial) AS buy_financial,
+                COUNT(*) AS buy_trades,
+                0 AS sell_volume,
+                0 AS sell_financial,
+                0 AS sell_trades
+            FROM
+                window_trades
+            WHERE
+                buyer_participant <> 0
+            GROUP BY
+                buyer_participant
+            UNION ALL
+            SELECT
+                seller_participant,
+                0, 0, 0,
+                SUM(negotiated_quantity),
+                SUM(financial),
+                COUNT(*)
+            FROM
+                window_trades
+            WHERE
+                seller_participant <> 0
+            GROUP BY
+                seller_participant
+        )
+        SELECT
+            f.participant,
+            COALESCE(b.name, ''),
+            SUM(f.buy_volume)::BIGINT,
+            SUM(f.sell_volume)::BIGINT,
+            SUM(f.buy_financial)::FLOAT8,
+            SUM(f.sell_financial)::FLOAT8,
+            SUM(f.buy_trades)::BIGINT,
+            SUM(f.sell_trades)::BIGINT
+        FROM
+            flows f
+        LEFT JOIN
+            brokers b ON b.participant_code = f.participant
+        GROUP BY
+            f.participant, b.name
+        ORDER BY
+            f.participant;
+    `
+
+	rows, err := r.pool.Query(ctx, query, q.InstrumentCode, q.From, q.To, nullableText(q.FromTime), nullableText(q.ToTime), q.Session)
+	if err != nil {
+		return nil, fmt.Errorf("repository: falha ao consultar fluxo por corretora: %w", err)
+	}
+	defer rows.Close()
+
+	positions := []entity.BrokerPosition{}
+	for rows.Next() {
+		var p entity.BrokerPosition
+		if err := rows.Scan(
+			&p.ParticipantCode, &p.Name,
+			&p.BuyVolume, &p.SellVolume,
+			&p.BuyFinancialVolume, &p.SellFinancialVolume,
+			&p.BuyTrades, &p.SellTrades,
+		); err != nil {
+			return nil, fmt.Errorf("repository: falha ao ler fluxo por corretora: %w", err)
+		}
+		positions = append(positions, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: falha ao iterar fluxo por corretora: %w", err)
+	}
+
+	return positions, nil
+}
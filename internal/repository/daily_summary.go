@@ -0,0 +1,312 @@
+// internal/repository/daily_summary.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// dailySummaryColumns são as colunas gravadas em daily_summaries, na ordem de dailySummaryRow.
+var dailySummaryColumns = []string{
+	"trade_date", "instrument_code", "open_price", "high_price", "low_price", "close_price",
+	"volume", "financial_volume", "trade_count", "vwap",
+}
+
+// dailySummaryRow converte um resumo para a ordem de dailySummaryColumns.
+func dailySummaryRow(s entity.DailySummary) []any {
+	return []any{
+		s.TradeDate, s.InstrumentCode, s.Open, s.High, s.Low, s.Close,
+		s.Volume, s.FinancialVolume, s.TradeCount, s.VWAP,
+	}
+}
+
+// replaceDailySummaries remove os resumos das datas informadas e grava os novos
+// dentro da transação recebida.
+func replaceDailySummaries(ctx context.Context, tx pgx.Tx, tradeDates []time.Time, summaries []entity.DailySummary) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM daily_summaries WHERE trade_date = ANY($1)", tradeDates); err != nil {
+		return fmt.Errorf("repository: falha ao remover resumos diários anteriores: %w", err)
+	}
+
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	copyCount, err := tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"daily_summaries"},
+		dailySummaryColumns,
+		pgx.CopyFromSlice(len(summaries), func(i int) ([]any, error) {
+			return dailySummaryRow(summaries[i]), nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("repository: falha ao gravar resumos diários: %w", err)
+	}
+	if copyCount != int64(len(summaries)) {
+		return fmt.Errorf("repository: número de resumos gravados (%d) não corresponde ao esperado (%d)", copyCount, len(summaries))
+	}
+
+	return nil
+}
+
+// ListTradeDates lista as datas presentes em 'trades' no intervalo [from, to].
+// Datas zeradas não limitam o intervalo. Com missingSummariesOnly, retorna apenas
+// as datas que ainda não possuem resumos em daily_summaries.
+func (r *postgresTradeRepository) ListTradeDates(ctx context.Context, from, to time.Time, missingSummariesOnly bool) ([]time.Time, error) {
+	query := `
+        SELECT DISTINCT
+            t.trade_date
+        FROM
+            trades t
+        WHERE
+            ($1::DATE IS NULL OR t.trade_date >= $1)
+            AND ($2::DATE IS NULL OR t.trade_date <= $2)
+            AND (NOT $3 OR NOT EXISTS (
+                SELECT 1 FROM daily_summaries s WHERE s.trade_date = t.trade_date
+            ))
+        ORDER BY
+            t.trade_date;
+    `
+
+	rows, err := r.pool.Query(ctx, query, nullableDate(from), nullableDate(to), missingSummariesOnly)
+	if err != nil {
+		return nil, fmt.Errorf("repository: falha ao listar datas de negociação: %w", err)
+	}
+	defer rows.Close()
+
+	var dates []time.Time
+	for rows.Next() {
+		var date time.Time
+		if err := rows.Scan(&date); err != nil {
+			return nil, fmt.Errorf("repository: falha ao ler data de negociação: %w", err)
+		}
+		dates = append(dates, date)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: falha ao iterar datas de negociação: %w", err)
+	}
+
+	return dates, nil
+}
+
+// RebuildDailySummaries recalcula os resumos de uma data a partir de 'trades',
+// usando a mesma ordem (closing_time, negotiated_price) da ingestão para abertura
+// e fechamento. Retorna a quantidade de resumos gravados.
+func (r *postgresTradeRepository) RebuildDailySummaries(ctx context.Context, tradeDate time.Time) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("repository: falha ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM daily_summaries WHERE trade_date = $1", tradeDate); err != nil {
+		return 0, fmt.Errorf("repository: falha ao remover resumos diários anteriores: %w", err)
+	}
+
+	query := `
+        INSERT INTO daily_summaries (
+            trade_date, instrument_code, open_price, high_price, low_price, close_price,
+            volume, financial_volume, trade_count, vwap
+        )
+        SELECT
+            trade_date,
+            instrument_code,
+            (ARRAY_AGG(negotiated_price ORDER BY closing_time, negotiated_price))[1],
+            MAX(negotiated_price),
+            MIN(negotiated_price),
+            (ARRAY_AGG(negotiated_price ORDER BY closing_time DESC, negotiated_price DESC))[1],
+            SUM(negotiated_quantity),
+            SUM(negotiated_price * negotiated_quantity),
+            COUNT(*),
+            ROUND(SUM(negotiated_price * negotiated_quantity) / NULLIF(SUM(negotiated_quantity), 0), 6)
+        FROM
+            trades
+        WHERE
+            trade_date = $1
+        GROUP BY
+            trade_date, instrument_code;
+    `
+
+	tag, err := tx.Exec(ctx, query, tradeDate)
+	if err != nil {
+		return 0, fmt.Errorf("repository: falha ao recalcular resumos diários: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("repository: falha ao fazer commit dos resumos diários: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// unsummarizedDatesQuery lista os pregões do instrumento ($1) entre $2 e $3 que têm
+// negociações em 'trades' e nenhum resumo em daily_summaries (datas carregadas antes do
+// recurso e ainda não recalculadas). Cada dia do intervalo é testado com consultas
+// pontuais aos índices por (instrument_code, trade_date), sem ler as negociações.
+// Limites nulos usam a primeira e a última data do instrumento em 'trades'.
+const unsummarizedDatesQuery = `
+        WITH bounds AS (
+            SELECT
+                COALESCE($2::DATE, (SELECT MIN(trade_date) FROM trades WHERE instrument_code = $1)) AS first_date,
+                COALESCE($3::DATE, (SELECT MAX(trade_date) FROM trades WHERE instrument_code = $1)) AS last_date
+        )
+        SELECT
+            d::DATE
+        FROM
+            bounds, generate_series(bounds.first_date, bounds.last_date, INTERVAL '1 day') AS d
+        WHERE
+            EXISTS (SELECT 1 FROM trades t WHERE t.instrument_code = $1 AND t.trade_date = d::DATE)
+            AND NOT EXISTS (SELECT 1 FROM daily_summaries s WHERE s.instrument_code = $1 AND s.trade_date = d::DATE)
+        ORDER BY
+            1;
+    `
+
+// summarizedDailyCTE define o CTE 'daily' com os pregões do instrumento ($1) entre $2 e
+// $3 (limites nulos não restringem): os resumos de daily_summaries e, para as datas $4
+// sem resumo (unsummarizedDates), o cálculo direto sobre 'trades'.
+var summarizedDailyCTE = `
+        WITH daily AS (
+            SELECT
+                trade_date, instrument_code, open_price, high_price, low_price, close_price,
+                volume, financial_volume, trade_count, vwap
+            FROM
+                daily_summaries
+            WHERE
+                instrument_code = $1
+                AND ($2::DATE IS NULL OR trade_date >= $2)
+                AND ($3::DATE IS NULL OR trade_date <= $3)
+            UNION ALL` + sessionDailyQuery("instrument_code = $1 AND trade_date = ANY($4::DATE[])") + `
+        )`
+
+// unsummarizedDates retorna as datas do instrumento em [from, to] com negociações e sem
+// resumos diários. Datas zeradas não limitam o intervalo.
+func (r *postgresTradeRepository) unsummarizedDates(ctx context.Context, instrumentCode string, from, to time.Time) ([]time.Time, error) {
+	rows, err := r.pool.Query(ctx, unsummarizedDatesQuery, instrumentCode, nullableDate(from), nullableDate(to))
+	if err != nil {
+		return nil, fmt.Errorf("repository: falha ao verificar datas sem resumo: %w", err)
+	}
+	defer rows.Close()
+
+	dates := []time.Time{}
+	for rows.Next() {
+		var date time.Time
+		if err := rows.Scan(&date); err != nil {
+			return nil, fmt.Errorf("repository: falha ao ler data sem resumo: %w", err)
+		}
+		dates = append(dates, date)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: falha ao iterar datas sem resumo: %w", err)
+	}
+	return dates, nil
+}
+
+// dailySeriesSummariesQuery lê a série diária do instrumento em daily_summaries.
+const dailySeriesSummariesQuery = `
+        SELECT
+            trade_date, open_price, high_price, low_price, close_price,
+            volume, financial_volume, trade_count
+        FROM
+            daily_summaries
+        WHERE
+            instrument_code = $1
+            AND ($2::DATE IS NULL OR trade_date >= $2)
+            AND ($3::DATE IS NULL OR trade_date <= $3)
+        ORDER BY
+            trade_date;
+    `
+
+// dailySeriesMergedQuery lê a série diária de daily_summaries e calcula em 'trades'
+// apenas as datas $4 que ainda não possuem resumo.
+var dailySeriesMergedQuery = summarizedDailyCTE + `
+        SELECT
+            trade_date, open_price, high_price, low_price, close_price,
+            volume, financial_volume, trade_count
+        FROM
+            daily
+        ORDER BY
+            trade_date;
+    `
+
+// dailySeriesSessionQuery calcula a série diária apenas com as negociações do tipo de
+// sessão $4. Diferente das demais, exige o intervalo completo.
+var dailySeriesSessionQuery = sessionDailyCTE + `
+        SELECT
+            trade_date, open_price, high_price, low_price, close_price,
+            volume, financial_volume, trade_count
+        FROM
+            daily
+        ORDER BY
+            trade_date;
+    `
+
+// GetDailySeries retorna a série diária OHLCV do instrumento no intervalo [from, to].
+// Datas zeradas não limitam o intervalo, exceto com 'session'. Assim como
+// GetAggregatedData, lê os resumos de daily_summaries e calcula em 'trades' os dias
+// ainda sem resumo, ou o período inteiro quando 'session' é informada.
+func (r *postgresTradeRepository) GetDailySeries(ctx context.Context, instrumentCode string, from, to time.Time, session *int) ([]entity.DailyOHLCV, error) {
+	var days []entity.DailyOHLCV
+	var err error
+	if session != nil {
+		if from.IsZero() || to.IsZero() {
+			return nil, fmt.Errorf("repository: série diária por sessão exige o intervalo completo")
+		}
+		days, err = r.queryDailySeries(ctx, dailySeriesSessionQuery, instrumentCode, from, to, *session)
+	} else {
+		var missing []time.Time
+		missing, err = r.unsummarizedDates(ctx, instrumentCode, from, to)
+		if err != nil {
+			return nil, err
+		}
+		if len(missing) == 0 {
+			days, err = r.queryDailySeries(ctx, dailySeriesSummariesQuery, instrumentCode, nullableDate(from), nullableDate(to))
+		} else {
+			days, err = r.queryDailySeries(ctx, dailySeriesMergedQuery, instrumentCode, nullableDate(from), nullableDate(to), missing)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(days) == 0 {
+		return nil, fmt.Errorf("repository: dados não encontrados")
+	}
+	return days, nil
+}
+
+func (r *postgresTradeRepository) queryDailySeries(ctx context.Context, query, instrumentCode string, args ...any) ([]entity.DailyOHLCV, error) {
+	rows, err := r.pool.Query(ctx, query, append([]any{instrumentCode}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("repository: falha ao consultar série diária: %w", err)
+	}
+	defer rows.Close()
+
+	var days []entity.DailyOHLCV
+	for rows.Next() {
+		var day entity.DailyOHLCV
+		if err := rows.Scan(
+			&day.TradeDate, &day.Open, &day.High, &day.Low, &day.Close,
+			&day.Volume, &day.FinancialVolume, &day.TradeCount,
+		); err != nil {
+			return nil, fmt.Errorf("repository: falha ao ler série diária: %w", err)
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: falha ao iterar série diária: %w", err)
+	}
+
+	return days, nil
+}
+
+// nullableDate converte uma data zerada em NULL para os filtros opcionais das consultas.
+func nullableDate(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,192 @@
+// internal/repository/outlier.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// OutlierRules define os critérios de DetectOutliers. Critérios zerados ficam desativados.
+type OutlierRules struct {
+	// BlockMultiple marca negócios com quantidade ou volume financeiro a partir deste
+	// múltiplo do tamanho médio por negócio do instrumento.
+	BlockMultiple float64
+	// HistoryDays é a janela, em dias corridos anteriores ao pregão, do tamanho médio por
+	// negócio (de daily_summaries). Sem histórico, usa a média do próprio pregão.
+	HistoryDays int
+	// PriceWindow é o número de negócios anteriores da média móvel de preço, contados
+	// dentro da mesma sessão; a marcação de preço só começa depois de PriceWindow
+	// negócios da sessão no dia.
+	PriceWindow int
+	// DeviationPct marca preços a partir deste desvio percentual da média móvel.
+	DeviationPct float64
+	// DeviationSigma marca preços a partir deste número de desvios padrão da média móvel.
+	DeviationSigma float64
+}
+
+// OutlierQuery filtra as marcações lidas por ListOutliers.
+type OutlierQuery struct {
+	From, To       time.Time // Intervalo de datas [From, To]
+	InstrumentCode string    // Vazio não filtra
+	Kind           string    // Vazio não filtra
+	Session        *int      // Tipo de sessão (entity.SessionRegular, ...); nil considera todas
+	Limit          int
+}
+
+// DetectOutliers marca as negociações de tradeDate que atendem a algum critério de rules
+// e substitui, na mesma transação, as marcações anteriores da data. Retorna o número de
+// marcações gravadas; um negócio pode receber mais de uma.
+func (r *postgresTradeRepository) DetectOutliers(ctx context.Context, tradeDate time.Time, rules OutlierRules) (int64, error) {
+	// O tamanho da janela móvel vai no texto da consulta: o PostgreSQL não aceita
+	// parâmetros na cláusula ROWS.
+	query := fmt.Sprintf(`
+        WITH history AS (
+            SELECT
+                instrument_code,
+                SUM(volume)::FLOAT8 / NULLIF(SUM(trade_count), 0) AS avg_quantity,
+                SUM(financial_volume)::FLOAT8 / NULLIF(SUM(trade_count), 0) AS avg_value
+            FROM
+                daily_summaries
+            WHERE
+                trade_date < $1 AND trade_date >= $1::DATE - $2::INT
+            GROUP BY
+                instrument_code
+        ),
+        today AS (
+            SELECT
+                instrument_code,
+                volume::FLOAT8 / NULLIF(trade_count, 0) AS avg_quantity,
+                financial_volume::FLOAT8 / NULLIF(trade_count, 0) AS avg_value
+            FROM
+                daily_summaries
+            WHERE
+                trade_date = $1
+        ),
+        scored AS (
+            SELECT
+                t.trade_date, t.instrument_code, t.closing_time, t.negotiated_price, t.negotiated_quantity,
+                t.buyer_participant, t.seller_participant, t.session_type,
+                t.negotiated_price * t.negotiated_quantity AS trade_value,
+                COALESCE(h.avg_quantity, d.avg_quantity) AS avg_quantity,
+                COALESCE(h.avg_value, d.avg_value) AS avg_value,
+                AVG(t.negotiated_price) OVER w AS rolling_mean,
+                STDDEV_SAMP(t.negotiated_price) OVER w AS rolling_std,
+                COUNT(*) OVER w AS rolling_count
+            FROM
+                trades t
+            LEFT JOIN
+                history h USING (instrument_code)
+            LEFT JOIN
+                today d USING (instrument_code)
+            WHERE
+                t.trade_date = $1
+            WINDOW w AS (
+                PARTITION BY t.instrument_code, t.session_type
+                ORDER BY t.closing_time, t.negotiated_price
+                ROWS BETWEEN %d PRECEDING AND 1 PRECEDING
+            )
+        )
+        INSERT INTO trade_outliers (
+            trade_date, instrument_code, closing_time, negotiated_price, negotiated_quantity,
+            buyer_participant, seller_participant, session_type, kind, score, reference, sigma
+        )
+        SELECT
+            trade_date, instrument_code, closing_time, negotiated_price, negotiated_quantity,
+            buyer_participant, seller_participant, session_type, '%s', negotiated_quantity / avg_quantity, avg_quantity, NULL
+        FROM
+            scored
+        WHERE
+            $3::FLOAT8 > 0 AND avg_quantity > 0 AND negotiated_quantity >= $3::FLOAT8 * avg_quantity
+        UNION ALL
+        SELECT
+            trade_date, instrument_code, closing_time, negotiated_price, negotiated_quantity,
+            buyer_participant, seller_participant, session_type, '%s', trade_value / avg_value, avg_value, NULL
+        FROM
+            scored
+        WHERE
+            $3::FLOAT8 > 0 AND avg_value > 0 AND trade_value >= $3::FLOAT8 * avg_value
+        UNION ALL
+        SELECT
+            trade_date, instrument_code, closing_time, negotiated_price, negotiated_quantity,
+            buyer_participant, seller_participant, session_type, '%s',
+            (negotiated_price / rolling_mean - 1) * 100, rolling_mean,
+            (negotiated_price - rolling_mean) / NULLIF(rolling_std, 0)
+        FROM
+            scored
+        WHERE
+            rolling_count >= %d AND rolling_mean > 0
+            AND (
+                ($4::FLOAT8 > 0 AND ABS(negotiated_price / rolling_mean - 1) * 100 >= $4::FLOAT8)
+                OR ($5::FLOAT8 > 0 AND rolling_std > 0 AND ABS(negotiated_price - rolling_mean) >= $5::FLOAT8 * rolling_std)
+            );
+    `, rules.PriceWindow, entity.OutlierBlockQuantity, entity.OutlierBlockValue, entity.OutlierPriceDeviation, rules.PriceWindow)
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("repository: falha ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM trade_outliers WHERE trade_date = $1`, tradeDate); err != nil {
+		return 0, fmt.Errorf("repository: falha ao remover outliers de %s: %w", tradeDate.Format("2006-01-02"), err)
+	}
+	tag, err := tx.Exec(ctx, query, tradeDate, rules.HistoryDays, rules.BlockMultiple, rules.DeviationPct, rules.DeviationSigma)
+	if err != nil {
+		return 0, fmt.Errorf("repository: falha ao detectar outliers de %s: %w", tradeDate.Format("2006-01-02"), err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("repository: falha ao confirmar outliers de %s: %w", tradeDate.Format("2006-01-02"), err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// ListOutliers lista as marcações que atendem ao filtro em ordem de data, instrumento
+// e horário, limitadas a q.Limit.
+func (r *postgresTradeRepository) ListOutliers(ctx context.Context, q OutlierQuery) ([]entity.TradeOutlier, error) {
+	query := `
+        SELECT
+            trade_date, instrument_code, closing_time, negotiated_price, negotiated_quantity,
+            buyer_participant, seller_participant, session_type, kind, score, reference, sigma
+        FROM
+            trade_outliers
+        WHERE
+            trade_date >= $1 AND trade_date <= $2
+            AND ($3::TEXT IS NULL OR instrument_code = $3)
+            AND ($4::TEXT IS NULL OR kind = $4)
+            AND ($5::SMALLINT IS NULL OR session_type = $5)
+        ORDER BY
+            trade_date, instrument_code, closing_time, kind
+        LIMIT $6;
+    `
+
+	rows, err := r.pool.Query(ctx, query, q.From, q.To, nullableText(q.InstrumentCode), nullableText(q.Kind), q.Session, q.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("repository: falha ao consultar outliers: %w", err)
+	}
+	defer rows.Close()
+
+	outliers := []entity.TradeOutlier{}
+	for rows.Next() {
+		var trade entity.Trade
+		var o entity.TradeOutlier
+		if err := rows.Scan(
+			&trade.TradeDate, &o.InstrumentCode, &trade.ClosingTime, &o.Price, &o.Quantity,
+			&o.BuyerParticipant, &o.SellerParticipant, &o.Session, &o.Kind, &o.Score, &o.Reference, &o.Sigma,
+		); err != nil {
+			return nil, fmt.Errorf("repository: falha ao ler outlier: %w", err)
+		}
+		if o.Time, err = trade.Timestamp(); err != nil {
+			return nil, fmt.Errorf("repository: %w", err)
+		}
+		outliers = append(outliers, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: falha ao iterar outliers: %w", err)
+	}
+
+	return outliers, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// A média móvel de preço é calculada por sessão: o after-market a 5% do pregão regular
+// não é marcado por comparação com os negócios regulares, e o filtro de sessão da
+// listagem separa as marcações de cada sessão.
+func TestDetectOutliersBySession(t *testing.T) {
+	repo := &postgresTradeRepository{pool: testDatabase(t)}
+	ctx := context.Background()
+	date := time.Date(2025, time.August, 29, 0, 0, 0, 0, time.UTC)
+
+	trade := func(minute int, price float64, session int) entity.Trade {
+		return entity.Trade{
+			TradeDate: date, InstrumentCode: "PETR4", NegotiatedPrice: price, NegotiatedQuantity: 100,
+			ClosingTime: fmt.Sprintf("1%02d00000", minute), SessionType: session, TradeID: int64(minute+1) * 10,
+		}
+	}
+	var trades []entity.Trade
+	for minute := 0; minute < 5; minute++ {
+		trades = append(trades, trade(minute, 10, entity.SessionRegular))
+	}
+	trades = append(trades, trade(5, 11, entity.SessionRegular)) // 10% acima da média regular
+	for minute := 6; minute < 11; minute++ {
+		trades = append(trades, trade(minute, 10.5, entity.SessionAfterMarket))
+	}
+
+	table, err := repo.CreateStagingTable(ctx)
+	if err != nil {
+		t.Fatalf("CreateStagingTable: %v", err)
+	}
+	defer repo.DropStagingTable(ctx, table)
+	if err := repo.SaveTrades(ctx, table, trades); err != nil {
+		t.Fatalf("SaveTrades: %v", err)
+	}
+	if err := repo.ReplaceTradeDates(ctx, table, []time.Time{date}, nil, nil); err != nil {
+		t.Fatalf("ReplaceTradeDates: %v", err)
+	}
+
+	count, err := repo.DetectOutliers(ctx, date, OutlierRules{PriceWindow: 3, DeviationPct: 2})
+	if err != nil {
+		t.Fatalf("DetectOutliers: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("%d marcação(ões), esperado 1 (somente o negócio regular a 11,00)", count)
+	}
+
+	for _, tt := range []struct {
+		name    string
+		session int
+		want    int
+	}{
+		{"regular", entity.SessionRegular, 1},
+		{"after-market", entity.SessionAfterMarket, 0},
+	} {
+		session := tt.session
+		outliers, err := repo.ListOutliers(ctx, OutlierQuery{From: date, To: date, Session: &session, Limit: 10})
+		if err != nil {
+			t.Fatalf("ListOutliers: %v", err)
+		}
+		if len(outliers) != tt.want {
+			t.Errorf("%s: %d marcação(ões), esperado %d", tt.name, len(outliers), tt.want)
+		}
+		for _, o := range outliers {
+			if o.Session != tt.session || o.Price != 11 {
+				t.Errorf("%s: marcação inesperada %+v", tt.name, o)
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// internal/repository/participant_pair.go
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// GetParticipantPairs agrupa as negociações que atendem ao filtro por par (comprador,
+// vendedor), com os nomes das corretoras quando mapeados em 'brokers'. Negociações sem
+// algum dos participantes (código 0) são ignoradas. As participações ficam a cargo do
+// chamador.
+func (r *postgresTradeRepository) GetParticipantPairs(ctx context.Context, q TradeQuery) ([]entity.ParticipantPair, error) {
+	query := `
+        WITH pairs AS (
+            SELECT
+                buyer_participant,
+                seller_participant,
+                COUNT(*) AS trade_count,
+                SUM(negotiated_quantity) AS volume,
+                SUM(negotiated_price * negotiated_quantity) AS financial_volume
+            FROM
+                trades
+            WHERE
+                instrument_code = $1
+                AND trade_date >= $2 AND trade_date <= $3
+                AND ($4::TEXT IS NULL OR closing_time >= $4)
+                AND ($5::TEXT IS NULL OR closing_time < $5)
+                AND ($6::SMALLINT IS NULL OR session_type = $6)
+                AND buyer_participant <> 0 AND seller_participant <> 0
+            GROUP BY
+                buyer_participant, seller_participant
+        )
+        SELECT
+            p.buyer_participant,
+            COALESCE(bb.name, ''),
+            p.seller_participant,
+            COALESCE(sb.name, ''),
+            p.trade_count,
+            p.volume::BIGINT,
+            p.financial_volume::FLOAT8
+        FROM
+            pairs p
+        LEFT JOIN
+            brokers bb ON bb.participant_code = p.buyer_participant
+        LEFT JOIN
+            brokers sb ON sb.participant_code = p.seller_participant
+        ORDER BY
+            p.volume DESC, p.buyer_participant, p.seller_participant;
+    `
+
+	rows, err := r.pool.Query(ctx, query, q.InstrumentCode, q.From, q.To, nullableText(q.FromTime), nullableText(q.ToTime), q.Session)
+	if err != nil {
+		return nil, fmt.Errorf("repository: falha ao consultar pares de participantes: %w", err)
+	}
+	defer rows.Close()
+
+	pairs := []entity.ParticipantPair{}
+	for rows.Next() {
+		var p entity.ParticipantPair
+		if err := rows.Scan(&p.Buyer, &p.BuyerName, &p.Seller, &p.SellerName, &p.TradeCount, &p.Volume, &p.FinancialVolume); err != nil {
+			return nil, fmt.Errorf("repository: falha ao ler par de participantes: %w", err)
+		}
+		p.SelfTrade = p.Buyer == p.Seller
+		pairs = append(pairs, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: falha ao iterar pares de participantes: %w", err)
+	}
+
+	return pairs, nil
+}
+
+// ListSelfTrades lista, em ordem de data e horário, as negociações que atendem ao
+// filtro em que comprador e vendedor são o mesmo participante identificado.
+func (r *postgresTradeRepository) ListSelfTrades(ctx context.Context, q TradeQuery) ([]entity.SelfTrade, error) {
+	query := `
+        SELECT
+            t.trade_date,
+            t.closing_time,
+            t.negotiated_price,
+            t.negotiated_quantity,
+            t.buyer_participant,
+            COALESCE(b.name, '')
+        FROM
+            trades t
+        LEFT JOIN
+            brokers b ON b.participant_code = t.buyer_participant
+        WHERE
+            t.instrument_code = $1
+            AND t.trade_date >= $2 AND t.trade_date <= $3
+            AND ($4::TEXT IS NULL OR t.closing_time >= $4)
+            AND ($5::TEXT IS NULL OR t.closing_time < $5)
+            AND ($6::SMALLINT IS NULL OR t.session_type = $6)
+            AND t.buyer_participant = t.seller_participant
+            AND t.buyer_participant <> 0
+        ORDER BY
+            t.trade_date, t.closing_time, t.negotiated_price;
+    `
+
+	rows, err := r.pool.Query(ctx, query, q.InstrumentCode, q.From, q.To, nullableText(q.FromTime), nullableText(q.ToTime), q.Session)
+	if err != nil {
+		return nil, fmt.Errorf("repository: falha ao consultar self-trades: %w", err)
+	}
+	defer rows.Close()
+
+	trades := []entity.SelfTrade{}
+	for rows.Next() {
+		var trade entity.Trade
+		var selfTrade entity.SelfTrade
+		if err := rows.Scan(&trade.TradeDate, &trade.ClosingTime, &selfTrade.Price, &selfTrade.Quantity, &selfTrade.Participant, &selfTrade.Name); err != nil {
+			return nil, fmt.Errorf("repository: falha ao ler self-trade: %w", err)
+		}
+		if selfTrade.Time, err = trade.Timestamp(); err != nil {
+			return nil, fmt.Errorf("repository: %w", err)
+		}
+		trades = append(trades, selfTrade)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: falha ao iterar self-trades: %w", err)
+	}
+
+	return trades, nil
+}
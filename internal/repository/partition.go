@@ -0,0 +1,101 @@
+// internal/repository/partition.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// PartitionRepository define as operações de manutenção das partições de 'trades'.
+type PartitionRepository interface {
+	ListTradePartitions(ctx context.Context) ([]entity.TradePartition, error)
+	DropTradePartition(ctx context.Context, name string) error
+}
+
+type postgresPartitionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresPartitionRepository cria uma nova instância de postgresPartitionRepository.
+func NewPostgresPartitionRepository(pool *pgxpool.Pool) PartitionRepository {
+	return &postgresPartitionRepository{pool: pool}
+}
+
+// partitionBoundRe extrai os limites de uma expressão "FOR VALUES FROM ('...') TO ('...')".
+var partitionBoundRe = regexp.MustCompile(`FROM \('(\d{4}-\d{2}-\d{2})'\) TO \('(\d{4}-\d{2}-\d{2})'\)`)
+
+// ListTradePartitions lista as partições anexadas a 'trades', ordenadas pelo limite inferior.
+// Partições sem limites de data (ex: DEFAULT) são ignoradas.
+func (r *postgresPartitionRepository) ListTradePartitions(ctx context.Context) ([]entity.TradePartition, error) {
+	query := `
+        SELECT
+            c.relname,
+            pg_get_expr(c.relpartbound, c.oid),
+            GREATEST(c.reltuples, 0)::BIGINT
+        FROM
+            pg_inherits i
+        JOIN
+            pg_class c ON c.oid = i.inhrelid
+        WHERE
+            i.inhparent = 'trades'::regclass
+        ORDER BY
+            c.relname;
+    `
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("repository: falha ao listar partições: %w", err)
+	}
+	defer rows.Close()
+
+	var partitions []entity.TradePartition
+	for rows.Next() {
+		var partition entity.TradePartition
+		var bound string
+		if err := rows.Scan(&partition.Name, &bound, &partition.EstimatedRows); err != nil {
+			return nil, fmt.Errorf("repository: falha ao ler partição: %w", err)
+		}
+
+		match := partitionBoundRe.FindStringSubmatch(bound)
+		if match == nil {
+			continue
+		}
+		if partition.From, err = time.Parse("2006-01-02", match[1]); err != nil {
+			return nil, fmt.Errorf("repository: limite inferior inválido na partição %s: %w", partition.Name, err)
+		}
+		if partition.To, err = time.Parse("2006-01-02", match[2]); err != nil {
+			return nil, fmt.Errorf("repository: limite superior inválido na partição %s: %w", partition.Name, err)
+		}
+		partitions = append(partitions, partition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: falha ao iterar partições: %w", err)
+	}
+
+	return partitions, nil
+}
+
+// DropTradePartition desanexa a partição de 'trades' sem bloquear leitores
+// (DETACH ... CONCURRENTLY) e em seguida remove a tabela.
+func (r *postgresPartitionRepository) DropTradePartition(ctx context.Context, name string) error {
+	partition := pgx.Identifier{name}.Sanitize()
+
+	// DETACH CONCURRENTLY não pode rodar dentro de um bloco de transação.
+	detach := fmt.Sprintf("ALTER TABLE trades DETACH PARTITION %s CONCURRENTLY", partition)
+	if _, err := r.pool.Exec(ctx, detach); err != nil {
+		return fmt.Errorf("repository: falha ao desanexar partição %s: %w", name, err)
+	}
+
+	if _, err := r.pool.Exec(ctx, fmt.Sprintf("DROP TABLE %s", partition)); err != nil {
+		return fmt.Errorf("repository: falha ao remover partição %s: %w", name, err)
+	}
+
+	return nil
+}
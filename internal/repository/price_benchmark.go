@@ -0,0 +1,98 @@
+// internal/repository/price_benchmark.go
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// closingTimeMillis converte closing_time ("HHMMSSmmm") em milissegundos desde a meia-noite.
+const closingTimeMillis = `(SUBSTRING(closing_time, 1, 2)::INT * 3600000 + SUBSTRING(closing_time, 3, 2)::INT * 60000 + SUBSTRING(closing_time, 5, 5)::INT)`
+
+// GetPriceBenchmarks calcula VWAP e TWAP das negociações que atendem ao filtro
+// inteiramente em NUMERIC, devolvendo os valores como texto decimal exato.
+//
+// No TWAP, o preço de cada negociação vale até a negociação seguinte do mesmo pregão;
+// a última negociação do dia vale até q.ToTime, se informado. Quando nenhum intervalo
+// tem duração (ex: uma única negociação sem fim de janela), usa a média simples dos preços.
+func (r *postgresTradeRepository) GetPriceBenchmarks(ctx context.Context, q TradeQuery) (*entity.PriceBenchmark, error) {
+	query := `
+        WITH window_trades AS (
+            SELECT
+                trade_date,
+                negotiated_price,
+                negotiated_quantity,
+                ` + closingTimeMillis + ` AS ms
+            FROM
+                trades
+            WHERE
+                instrument_code = $1
+                AND trade_date >= $2 AND trade_date <= $3
+                AND ($4::TEXT IS NULL OR closing_time >= $4)
+                AND ($5::TEXT IS NULL OR closing_time < $5)
+                AND ($7::SMALLINT IS NULL OR session_type = $7)
+        ),
+        timed AS (
+            SELECT
+                trade_date,
+                negotiated_price,
+                negotiated_quantity,
+                COALESCE(
+                    LEAD(ms) OVER (PARTITION BY trade_date ORDER BY ms, negotiated_price),
+                    $6::INT
+                ) - ms AS duration
+            FROM
+                window_trades
+        )
+        SELECT
+            COUNT(*),
+            COALESCE(SUM(negotiated_quantity), 0),
+            COALESCE(SUM(negotiated_price * negotiated_quantity), 0)::TEXT,
+            COALESCE(ROUND(SUM(negotiated_price * negotiated_quantity) / NULLIF(SUM(negotiated_quantity), 0), 6), 0)::TEXT,
+            COALESCE(
+                ROUND(SUM(negotiated_price * duration) / NULLIF(SUM(duration), 0), 6),
+                ROUND(AVG(negotiated_price), 6),
+                0
+            )::TEXT,
+            COUNT(DISTINCT trade_date)
+        FROM
+            timed;
+    `
+
+	var windowEnd *int
+	if q.ToTime != "" {
+		end, err := entity.TradeTimestamp(q.To, q.ToTime)
+		if err != nil {
+			return nil, fmt.Errorf("repository: %w", err)
+		}
+		midnight := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location())
+		ms := int(end.Sub(midnight).Milliseconds())
+		windowEnd = &ms
+	}
+
+	result := entity.PriceBenchmark{InstrumentCode: q.InstrumentCode, From: q.From, To: q.To}
+	var financialVolume, vwap, twap string
+	err := r.pool.QueryRow(ctx, query, q.InstrumentCode, q.From, q.To, nullableText(q.FromTime), nullableText(q.ToTime), windowEnd, q.Session).Scan(
+		&result.TradeCount,
+		&result.Volume,
+		&financialVolume,
+		&vwap,
+		&twap,
+		&result.TradingDays,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repository: falha ao calcular VWAP/TWAP: %w", err)
+	}
+	if result.TradeCount == 0 {
+		return nil, fmt.Errorf("repository: dados não encontrados")
+	}
+
+	result.FinancialVolume = json.Number(financialVolume)
+	result.VWAP = json.Number(vwap)
+	result.TWAP = json.Number(twap)
+	return &result, nil
+}
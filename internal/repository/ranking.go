@@ -0,0 +1,152 @@
+// internal/repository/ranking.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// RankingMetrics mapeia as métricas aceitas para a coluna ordenada em GetRankings.
+var RankingMetrics = map[string]string{
+	"volume":           "volume",
+	"financial_volume": "financial_volume",
+	"trades":           "trade_count",
+	"change_pct":       "change_pct",
+}
+
+// RankingQuery define o ranking calculado por GetRankings.
+type RankingQuery struct {
+	From, To   time.Time // Intervalo de pregões [From, To]
+	Metric     string    // Chave de RankingMetrics
+	AssetClass string    // Classe de entity.AssetClassPatterns; vazio não filtra
+	Limit      int       // Tamanho de cada lista (maiores e menores)
+	Session    *int      // Tipo de sessão; nil usa daily_summaries (todas as sessões)
+}
+
+// referenceLookbackDays limita a busca do fechamento de referência para change_pct.
+const referenceLookbackDays = 15
+
+// assetClassCase gera a expressão SQL que classifica instrument_code com os mesmos
+// padrões, e na mesma ordem, de entity.AssetClassOf.
+func assetClassCase() string {
+	var b strings.Builder
+	b.WriteString("CASE")
+	for _, p := range entity.AssetClassPatterns {
+		fmt.Fprintf(&b, " WHEN instrument_code ~ '%s' THEN '%s'", p.Pattern, p.Name)
+	}
+	fmt.Fprintf(&b, " ELSE '%s' END", entity.AssetClassOther)
+	return b.String()
+}
+
+// GetRankings ordena os instrumentos pela métrica no intervalo, a partir de
+// daily_summaries (ou de 'trades', com Session), e retorna os Limit maiores e os Limit
+// menores. change_pct compara o último fechamento do intervalo com o fechamento do
+// pregão anterior a From ou, sem ele, com a abertura do primeiro pregão; instrumentos
+// sem referência ficam de fora.
+func (r *postgresTradeRepository) GetRankings(ctx context.Context, q RankingQuery) (top, bottom []entity.RankingEntry, err error) {
+	column, ok := RankingMetrics[q.Metric]
+	if !ok {
+		return nil, nil, fmt.Errorf("repository: métrica de ranking desconhecida '%s'", q.Metric)
+	}
+
+	sessionCTE, source := summarySource(q.Session, "$5", "$1::DATE - "+fmt.Sprint(referenceLookbackDays), "$2")
+	query := `
+        WITH ` + sessionCTE + `
+        period AS (
+            SELECT
+                instrument_code,
+                SUM(volume) AS volume,
+                SUM(financial_volume) AS financial_volume,
+                SUM(trade_count) AS trade_count,
+                (ARRAY_AGG(open_price ORDER BY trade_date))[1] AS first_open,
+                (ARRAY_AGG(close_price ORDER BY trade_date DESC))[1] AS last_close
+            FROM
+                ` + source + `
+            WHERE
+                trade_date >= $1 AND trade_date <= $2
+            GROUP BY
+                instrument_code
+        ),
+        reference AS (
+            SELECT DISTINCT ON (instrument_code)
+                instrument_code, close_price
+            FROM
+                ` + source + `
+            WHERE
+                trade_date < $1 AND trade_date >= $1::DATE - ` + fmt.Sprint(referenceLookbackDays) + `
+            ORDER BY
+                instrument_code, trade_date DESC
+        ),
+        metrics AS (
+            SELECT
+                p.*,
+                ROUND((p.last_close / NULLIF(COALESCE(r.close_price, p.first_open), 0) - 1) * 100, 4) AS change_pct,
+                ` + assetClassCase() + ` AS asset_class
+            FROM
+                period p
+            LEFT JOIN
+                reference r USING (instrument_code)
+        ),
+        ranked AS (
+            SELECT
+                m.*,
+                ` + column + ` AS value,
+                ROW_NUMBER() OVER (ORDER BY ` + column + ` DESC, instrument_code) AS top_rank,
+                ROW_NUMBER() OVER (ORDER BY ` + column + ` ASC, instrument_code) AS bottom_rank
+            FROM
+                metrics m
+            WHERE
+                ($3::TEXT IS NULL OR asset_class = $3)
+                AND ` + column + ` IS NOT NULL
+        )
+        SELECT
+            top_rank, bottom_rank, instrument_code, asset_class, value,
+            volume, financial_volume, trade_count, last_close, change_pct
+        FROM
+            ranked
+        WHERE
+            top_rank <= $4 OR bottom_rank <= $4;
+    `
+
+	args := []any{q.From, q.To, nullableText(q.AssetClass), q.Limit}
+	if q.Session != nil {
+		args = append(args, *q.Session)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("repository: falha ao calcular ranking: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var topRank, bottomRank int
+		var entry entity.RankingEntry
+		if err := rows.Scan(
+			&topRank, &bottomRank, &entry.InstrumentCode, &entry.AssetClass, &entry.Value,
+			&entry.Volume, &entry.FinancialVolume, &entry.TradeCount, &entry.Close, &entry.ChangePct,
+		); err != nil {
+			return nil, nil, fmt.Errorf("repository: falha ao ler ranking: %w", err)
+		}
+		if topRank <= q.Limit {
+			entry.Rank = topRank
+			top = append(top, entry)
+		}
+		if bottomRank <= q.Limit {
+			entry.Rank = bottomRank
+			bottom = append(bottom, entry)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("repository: falha ao iterar ranking: %w", err)
+	}
+
+	sort.Slice(top, func(i, j int) bool { return top[i].Rank < top[j].Rank })
+	sort.Slice(bottom, func(i, j int) bool { return bottom[i].Rank < bottom[j].Rank })
+	return top, bottom, nil
+}
@@ -0,0 +1,217 @@
+// internal/repository/screener.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// ScreenerFields mapeia os campos aceitos nos filtros e na ordenação do screener para
+// as colunas calculadas em ScreenDailyMetrics. Todos são numéricos, exceto asset_class.
+var ScreenerFields = map[string]string{
+	"open":             "open_price",
+	"high":             "high_price",
+	"low":              "low_price",
+	"close":            "close_price",
+	"vwap":             "vwap",
+	"change_pct":       "change_pct",
+	"volume":           "volume",
+	"volume_avg":       "volume_avg",
+	"volume_ratio":     "volume_ratio",
+	"financial_volume": "financial_volume",
+	"trade_count":      "trade_count",
+	"asset_class":      "asset_class",
+}
+
+// ScreenerFilter é uma condição do screener. Op é um de =, !=, >, >=, <, <=
+// (um valor), between (dois valores, inclusivos) ou in (um único valor com a lista
+// aceita, []float64 ou []string).
+type ScreenerFilter struct {
+	Field  string
+	Op     string
+	Values []any // float64 para campos numéricos, string para asset_class
+}
+
+// ScreenerSort é um critério de ordenação do screener.
+type ScreenerSort struct {
+	Field      string
+	Descending bool
+}
+
+// ScreenerQuery define uma consulta do screener sobre as métricas de um pregão.
+type ScreenerQuery struct {
+	Date          time.Time
+	VolumeAvgDays int // Pregões anteriores usados em volume_avg e volume_ratio
+	Filters       []ScreenerFilter
+	Sort          []ScreenerSort
+	Limit, Offset int
+	Session       *int // Tipo de sessão; nil usa daily_summaries (todas as sessões)
+}
+
+// screenerComparisons são os operadores de um valor aceitos nos filtros.
+var screenerComparisons = map[string]bool{"=": true, "!=": true, ">": true, ">=": true, "<": true, "<=": true}
+
+// ScreenDailyMetrics calcula as métricas de todos os instrumentos no pregão, a partir
+// de daily_summaries (ou de 'trades', com Session), aplica os filtros (combinados com AND) e retorna a página pedida
+// junto com o total de instrumentos que atendem aos filtros. O total vem da própria
+// página (COUNT(*) OVER ()); quando o deslocamento passa do fim e a página volta
+// vazia, é obtido com uma contagem à parte.
+func (r *postgresTradeRepository) ScreenDailyMetrics(ctx context.Context, q ScreenerQuery) ([]entity.ScreenerRow, int64, error) {
+	args := []any{q.Date, q.VolumeAvgDays}
+	placeholder := func(value any, sqlType string) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d::%s", len(args), sqlType)
+	}
+
+	sessionCTE, source := "", "daily_summaries"
+	if q.Session != nil {
+		sessionCTE, source = summarySource(q.Session, placeholder(*q.Session, "SMALLINT"),
+			"$1::DATE - ($2::INT * 2 + "+fmt.Sprint(referenceLookbackDays)+")", "$1")
+	}
+
+	conditions := []string{"TRUE"}
+	for _, f := range q.Filters {
+		column, ok := ScreenerFields[f.Field]
+		if !ok {
+			return nil, 0, fmt.Errorf("repository: campo de screener desconhecido '%s'", f.Field)
+		}
+		sqlType := "NUMERIC"
+		if f.Field == "asset_class" {
+			sqlType = "TEXT"
+		}
+		switch {
+		case screenerComparisons[f.Op] && len(f.Values) == 1:
+			conditions = append(conditions, fmt.Sprintf("%s %s %s", column, f.Op, placeholder(f.Values[0], sqlType)))
+		case f.Op == "between" && len(f.Values) == 2:
+			conditions = append(conditions, fmt.Sprintf("%s BETWEEN %s AND %s", column, placeholder(f.Values[0], sqlType), placeholder(f.Values[1], sqlType)))
+		case f.Op == "in" && len(f.Values) == 1:
+			conditions = append(conditions, fmt.Sprintf("%s = ANY(%s)", column, placeholder(f.Values[0], sqlType+"[]")))
+		default:
+			return nil, 0, fmt.Errorf("repository: filtro de screener inválido (%s %s com %d valores)", f.Field, f.Op, len(f.Values))
+		}
+	}
+
+	orderBy := make([]string, 0, len(q.Sort)+1)
+	for _, s := range q.Sort {
+		column, ok := ScreenerFields[s.Field]
+		if !ok {
+			return nil, 0, fmt.Errorf("repository: campo de ordenação desconhecido '%s'", s.Field)
+		}
+		direction := "ASC NULLS LAST"
+		if s.Descending {
+			direction = "DESC NULLS LAST"
+		}
+		orderBy = append(orderBy, column+" "+direction)
+	}
+	orderBy = append(orderBy, "instrument_code")
+
+	metrics := `
+        WITH ` + sessionCTE + `
+        day AS (
+            SELECT
+                instrument_code, open_price, high_price, low_price, close_price, vwap,
+                volume, financial_volume, trade_count
+            FROM
+                ` + source + `
+            WHERE
+                trade_date = $1
+        ),
+        reference AS (
+            SELECT DISTINCT ON (instrument_code)
+                instrument_code, close_price
+            FROM
+                ` + source + `
+            WHERE
+                trade_date < $1 AND trade_date >= $1::DATE - ` + fmt.Sprint(referenceLookbackDays) + `
+            ORDER BY
+                instrument_code, trade_date DESC
+        ),
+        history AS (
+            SELECT
+                instrument_code, AVG(volume) AS volume_avg
+            FROM (
+                SELECT
+                    instrument_code,
+                    volume,
+                    ROW_NUMBER() OVER (PARTITION BY instrument_code ORDER BY trade_date DESC) AS rn
+                FROM
+                    ` + source + `
+                WHERE
+                    trade_date < $1 AND trade_date >= $1::DATE - ($2::INT * 2 + ` + fmt.Sprint(referenceLookbackDays) + `)
+            ) recent
+            WHERE
+                rn <= $2
+            GROUP BY
+                instrument_code
+        ),
+        metrics AS (
+            SELECT
+                d.*,
+                ROUND((d.close_price / NULLIF(COALESCE(r.close_price, d.open_price), 0) - 1) * 100, 4) AS change_pct,
+                ROUND(h.volume_avg, 2) AS volume_avg,
+                ROUND(d.volume / NULLIF(h.volume_avg, 0), 4) AS volume_ratio,
+                ` + assetClassCase() + ` AS asset_class
+            FROM
+                day d
+            LEFT JOIN
+                reference r USING (instrument_code)
+            LEFT JOIN
+                history h USING (instrument_code)
+        )`
+	where := strings.Join(conditions, " AND ")
+
+	// Limite e deslocamento são os últimos parâmetros, para que a contagem use apenas os anteriores.
+	countArgs := args
+	limit, offset := placeholder(q.Limit, "INT"), placeholder(q.Offset, "INT")
+	query := metrics + `
+        SELECT
+            instrument_code, asset_class, open_price, high_price, low_price, close_price, vwap,
+            change_pct, volume, volume_avg, volume_ratio, financial_volume, trade_count,
+            COUNT(*) OVER () AS total
+        FROM
+            metrics
+        WHERE
+            ` + where + `
+        ORDER BY
+            ` + strings.Join(orderBy, ", ") + `
+        LIMIT ` + limit + ` OFFSET ` + offset + `;
+    `
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("repository: falha ao executar screener: %w", err)
+	}
+	defer rows.Close()
+
+	var total int64
+	results := []entity.ScreenerRow{}
+	for rows.Next() {
+		var row entity.ScreenerRow
+		if err := rows.Scan(
+			&row.InstrumentCode, &row.AssetClass, &row.Open, &row.High, &row.Low, &row.Close, &row.VWAP,
+			&row.ChangePct, &row.Volume, &row.VolumeAvg, &row.VolumeRatio, &row.FinancialVolume, &row.TradeCount,
+			&total,
+		); err != nil {
+			return nil, 0, fmt.Errorf("repository: falha ao ler resultado do screener: %w", err)
+		}
+		results = append(results, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("repository: falha ao iterar resultado do screener: %w", err)
+	}
+
+	if len(results) == 0 && q.Offset > 0 {
+		countQuery := metrics + `
+        SELECT COUNT(*) FROM metrics WHERE ` + where + `;
+    `
+		if err := r.pool.QueryRow(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+			return nil, 0, fmt.Errorf("repository: falha ao contar resultado do screener: %w", err)
+		}
+	}
+
+	return results, total, nil
+}
@@ -0,0 +1,101 @@
+// internal/repository/session.go
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// sessionDailyQuery agrupa por pregão e instrumento as negociações que atendem a 'where',
+// com as mesmas colunas de daily_summaries e a mesma ordem (closing_time,
+// negotiated_price) da ingestão para abertura e fechamento. É a fonte das consultas
+// restritas a um tipo de sessão, já que daily_summaries soma todas as sessões do dia.
+func sessionDailyQuery(where string) string {
+	return `
+            SELECT
+                trade_date,
+                instrument_code,
+                (ARRAY_AGG(negotiated_price ORDER BY closing_time, negotiated_price))[1] AS open_price,
+                MAX(negotiated_price) AS high_price,
+                MIN(negotiated_price) AS low_price,
+                (ARRAY_AGG(negotiated_price ORDER BY closing_time DESC, negotiated_price DESC))[1] AS close_price,
+                SUM(negotiated_quantity) AS volume,
+                SUM(negotiated_price * negotiated_quantity) AS financial_volume,
+                COUNT(*) AS trade_count,
+                ROUND(SUM(negotiated_price * negotiated_quantity) / NULLIF(SUM(negotiated_quantity), 0), 6) AS vwap
+            FROM
+                trades
+            WHERE
+                ` + where + `
+            GROUP BY
+                trade_date, instrument_code`
+}
+
+// sessionDailyCTE define o CTE 'daily' com os pregões do instrumento ($1) entre $2 e $3
+// apenas no tipo de sessão $4.
+var sessionDailyCTE = `
+        WITH daily AS (` + sessionDailyQuery("instrument_code = $1 AND trade_date >= $2 AND trade_date <= $3 AND session_type = $4") + `
+        )`
+
+// summarySource retorna a relação de resumos diários lida pelas consultas de mercado e
+// o CTE que a define, a ser incluído na cláusula WITH. Sem sessão, a fonte é
+// daily_summaries e o CTE é vazio; com sessão, os resumos são calculados de 'trades'
+// apenas para o tipo de sessão no parâmetro sessionParam, entre as expressões SQL
+// 'from' e 'to'.
+func summarySource(session *int, sessionParam, from, to string) (cte, source string) {
+	if session == nil {
+		return "", "daily_summaries"
+	}
+	where := fmt.Sprintf("session_type = %s AND trade_date >= %s AND trade_date <= %s", sessionParam, from, to)
+	return "session_summaries AS (" + sessionDailyQuery(where) + `
+        ),`, "session_summaries"
+}
+
+// GetSessionBreakdown resume, por tipo de sessão, as negociações que atendem ao filtro.
+// O filtro de sessão de q é ignorado.
+func (r *postgresTradeRepository) GetSessionBreakdown(ctx context.Context, q TradeQuery) ([]entity.SessionStats, error) {
+	query := `
+        SELECT
+            session_type,
+            SUM(negotiated_quantity)::BIGINT,
+            SUM(negotiated_price * negotiated_quantity)::FLOAT8,
+            COUNT(*),
+            MAX(negotiated_price),
+            MIN(negotiated_price),
+            ROUND(SUM(negotiated_price * negotiated_quantity) / NULLIF(SUM(negotiated_quantity), 0), 6),
+            COUNT(DISTINCT trade_date)
+        FROM
+            trades
+        WHERE
+            instrument_code = $1
+            AND trade_date >= $2 AND trade_date <= $3
+            AND ($4::TEXT IS NULL OR closing_time >= $4)
+            AND ($5::TEXT IS NULL OR closing_time < $5)
+        GROUP BY
+            session_type
+        ORDER BY
+            session_type;
+    `
+
+	rows, err := r.pool.Query(ctx, query, q.InstrumentCode, q.From, q.To, nullableText(q.FromTime), nullableText(q.ToTime))
+	if err != nil {
+		return nil, fmt.Errorf("repository: falha ao consultar negociações por sessão: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []entity.SessionStats{}
+	for rows.Next() {
+		var s entity.SessionStats
+		if err := rows.Scan(&s.Session, &s.Volume, &s.FinancialVolume, &s.TradeCount, &s.High, &s.Low, &s.VWAP, &s.TradingDays); err != nil {
+			return nil, fmt.Errorf("repository: falha ao ler negociações por sessão: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: falha ao iterar negociações por sessão: %w", err)
+	}
+
+	return sessions, nil
+}
@@ -3,6 +3,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,8 +15,35 @@ import (
 
 // TradeRepository define a interface para operações de banco de dados relacionadas a trades.
 type TradeRepository interface {
-	SaveTrades(ctx context.Context, trades []entity.Trade) error
-	GetAggregatedData(ctx context.Context, instrumentCode string, startDate time.Time) (*entity.AggregatedData, error)
+	CreateStagingTable(ctx context.Context) (string, error)
+	DropStagingTable(ctx context.Context, table string) error
+	SaveTrades(ctx context.Context, table string, trades []entity.Trade) error
+	StreamTrades(ctx context.Context, table string, tradeCh <-chan entity.Trade, commitInterval int, onRow func(*entity.Trade)) (int64, error)
+	GetStagingChecksums(ctx context.Context, table string) (map[string]entity.TradeChecksum, error)
+	ReplaceTradeDates(ctx context.Context, table string, tradeDates []time.Time, summaries []entity.DailySummary, idChecks []entity.TradeIDCheck) error
+	ListTradeDates(ctx context.Context, from, to time.Time, missingSummariesOnly bool) ([]time.Time, error)
+	RebuildDailySummaries(ctx context.Context, tradeDate time.Time) (int64, error)
+	GetAggregatedData(ctx context.Context, instrumentCode string, startDate, endDate time.Time, session *int) (*entity.AggregatedData, error)
+	GetAggregatedStatistics(ctx context.Context, instrumentCode string, startDate, endDate time.Time, session *int) (*entity.AggregatedData, error)
+	GetAggregatedDataBatch(ctx context.Context, queries []AggregatedQuery, session *int) ([]*entity.AggregatedData, error)
+	GetRankings(ctx context.Context, q RankingQuery) (top, bottom []entity.RankingEntry, err error)
+	ScreenDailyMetrics(ctx context.Context, q ScreenerQuery) ([]entity.ScreenerRow, int64, error)
+	GetDailySeries(ctx context.Context, instrumentCode string, from, to time.Time, session *int) ([]entity.DailyOHLCV, error)
+	ScanTrades(ctx context.Context, q TradeQuery, fn func(*entity.Trade) error) error
+	GetPriceBenchmarks(ctx context.Context, q TradeQuery) (*entity.PriceBenchmark, error)
+	GetBrokerFlows(ctx context.Context, q TradeQuery) ([]entity.BrokerPosition, error)
+	GetParticipantPairs(ctx context.Context, q TradeQuery) ([]entity.ParticipantPair, error)
+	ListSelfTrades(ctx context.Context, q TradeQuery) ([]entity.SelfTrade, error)
+	GetSessionBreakdown(ctx context.Context, q TradeQuery) ([]entity.SessionStats, error)
+	GetPriceLevels(ctx context.Context, q TradeQuery) ([]entity.PriceLevel, error)
+	GetIntradayVariance(ctx context.Context, q TradeQuery, minutes int) ([]entity.IntradayVariance, error)
+	DetectOutliers(ctx context.Context, tradeDate time.Time, rules OutlierRules) (int64, error)
+	ListOutliers(ctx context.Context, q OutlierQuery) ([]entity.TradeOutlier, error)
+	CheckTradeIDs(ctx context.Context, table string, tradeDates []time.Time, step int64) ([]entity.TradeIDCheck, error)
+	ReplaceTradeIDIssues(ctx context.Context, checks []entity.TradeIDCheck) error
+	GetLoadTotals(ctx context.Context, tradeDates []time.Time) (map[string]map[string]entity.LoadTotals, error)
+	RecordTradeLoads(ctx context.Context, fileName string, tradeDates []time.Time, tradeCounts []int64) error
+	RecordLoadVerification(ctx context.Context, fileName string, tradeDates []time.Time, tradeCounts []int64, discrepancies []int32, rejected int64) error
 }
 
 type postgresTradeRepository struct {
@@ -27,9 +55,9 @@ func NewPostgresTradeRepository(pool *pgxpool.Pool) TradeRepository {
 	return &postgresTradeRepository{pool: pool}
 }
 
-// SaveTrades persiste um lote de negociações no banco de dados usando COPY FROM do pgx.
+// SaveTrades persiste um lote de negociações na tabela informada usando COPY FROM do pgx.
 // Esta implementação é muito mais performática para ingestão em massa (565MB).
-func (r *postgresTradeRepository) SaveTrades(ctx context.Context, trades []entity.Trade) error {
+func (r *postgresTradeRepository) SaveTrades(ctx context.Context, table string, trades []entity.Trade) error {
 	if len(trades) == 0 {
 		return nil
 	}
@@ -42,8 +70,8 @@ func (r *postgresTradeRepository) SaveTrades(ctx context.Context, trades []entit
 
 	copyCount, err := tx.CopyFrom(
 		ctx,
-		pgx.Identifier{"trades"},
-		[]string{"trade_date", "instrument_code", "negotiated_price", "negotiated_quantity", "closing_time"},
+		pgx.Identifier{table},
+		tradeColumns,
 		pgx.CopyFromRows(r.tradesToRows(trades)),
 	)
 	if err != nil {
@@ -63,6 +91,49 @@ func (r *postgresTradeRepository) SaveTrades(ctx context.Context, trades []entit
 	return nil
 }
 
+// StreamTrades mantém um único COPY FROM aberto por chamada na tabela informada, alimentado
+// diretamente pelo canal do pipeline através de tradeCopySource.
+// A cada 'commitInterval' linhas o COPY é encerrado e a transação confirmada,
+// e um novo ciclo é iniciado na mesma conexão até o canal ser fechado.
+// Um 'commitInterval' menor ou igual a zero mantém tudo em uma única transação.
+func (r *postgresTradeRepository) StreamTrades(ctx context.Context, table string, tradeCh <-chan entity.Trade, commitInterval int, onRow func(*entity.Trade)) (int64, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("repository: falha ao obter conexão do pool: %w", err)
+	}
+	defer conn.Release()
+
+	src := newTradeCopySource(ctx, tradeCh, commitInterval, onRow)
+	var total int64
+
+	for !src.drained {
+		src.reset()
+
+		tx, err := conn.Begin(ctx)
+		if err != nil {
+			return total, fmt.Errorf("repository: falha ao iniciar transação: %w", err)
+		}
+
+		copyCount, err := tx.CopyFrom(ctx, pgx.Identifier{table}, tradeColumns, src)
+		if err != nil {
+			tx.Rollback(ctx)
+			return total, fmt.Errorf("repository: falha ao executar COPY FROM em streaming: %w", err)
+		}
+
+		if copyCount != int64(src.count) {
+			tx.Rollback(ctx)
+			return total, fmt.Errorf("repository: número de linhas copiadas (%d) não corresponde ao esperado (%d)", copyCount, src.count)
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return total, fmt.Errorf("repository: falha ao fazer commit da transação: %w", err)
+		}
+		total += copyCount
+	}
+
+	return total, nil
+}
+
 // tradesToRows converte um slice de Trade para um slice de []interface{} para o COPY FROM.
 func (r *postgresTradeRepository) tradesToRows(trades []entity.Trade) [][]interface{} {
 	rows := make([][]interface{}, len(trades))
@@ -73,46 +144,121 @@ func (r *postgresTradeRepository) tradesToRows(trades []entity.Trade) [][]interf
 			trade.NegotiatedPrice,
 			trade.NegotiatedQuantity,
 			trade.ClosingTime,
+			trade.BuyerParticipant,
+			trade.SellerParticipant,
+			trade.SessionType,
+			trade.TradeID,
 		}
 	}
 	return rows
 }
 
-func (r *postgresTradeRepository) GetAggregatedData(ctx context.Context, instrumentCode string, startDate time.Time) (*entity.AggregatedData, error) {
-	query := `
+// AggregatedSummariesQuery calcula os dados agregados a partir de daily_summaries,
+// lendo uma linha por pregão do instrumento.
+const AggregatedSummariesQuery = `
         SELECT
-            MAX(t.negotiated_price) AS max_range_value,
-            MAX(daily_volume.total_volume) AS max_daily_volume,
-            $1 AS instrument_code
+            MAX(high_price) AS max_range_value,
+            MAX(volume) AS max_daily_volume
         FROM
-            trades t
-        JOIN (
+            daily_summaries
+        WHERE
+            instrument_code = $1 AND trade_date >= $2 AND trade_date <= $3
+        HAVING
+            COUNT(*) > 0;
+    `
+
+// AggregatedTradesQuery calcula os dados agregados diretamente de 'trades' em uma
+// única passada: o CTE agrupa por dia o maior preço e o volume total e a consulta
+// externa toma os máximos entre os dias. Com o índice de cobertura
+// idx_trades_instrument_date_covering o PostgreSQL resolve tudo com um index-only scan.
+const AggregatedTradesQuery = `
+        WITH daily AS (
             SELECT
                 trade_date,
+                MAX(negotiated_price) AS max_price,
                 SUM(negotiated_quantity) AS total_volume
             FROM
                 trades
             WHERE
-                instrument_code = $1 AND trade_date >= $2
+                instrument_code = $1 AND trade_date >= $2 AND trade_date <= $3
             GROUP BY
                 trade_date
-        ) AS daily_volume ON t.trade_date = daily_volume.trade_date AND t.instrument_code = $1
-        WHERE
-            t.instrument_code = $1 AND t.trade_date >= $2;
+        )
+        SELECT
+            MAX(max_price) AS max_range_value,
+            MAX(total_volume) AS max_daily_volume
+        FROM
+            daily
+        HAVING
+            COUNT(*) > 0;
     `
 
-	var result entity.AggregatedData
-	err := r.pool.QueryRow(ctx, query, instrumentCode, startDate).Scan(
-		&result.MaxRangeValue,
-		&result.MaxDailyVolume,
-		&result.InstrumentCode,
-	)
+// aggregatedMergedQuery calcula os dados agregados quando parte do período não tem
+// resumos: os pregões resumidos vêm de daily_summaries e as datas $4 de 'trades'.
+var aggregatedMergedQuery = summarizedDailyCTE + `
+        SELECT
+            MAX(high_price) AS max_range_value,
+            MAX(volume) AS max_daily_volume
+        FROM
+            daily
+        HAVING
+            COUNT(*) > 0;
+    `
+
+// aggregatedSessionQuery calcula os dados agregados apenas das negociações do tipo de sessão $4.
+var aggregatedSessionQuery = sessionDailyCTE + `
+        SELECT
+            MAX(high_price) AS max_range_value,
+            MAX(volume) AS max_daily_volume
+        FROM
+            daily
+        HAVING
+            COUNT(*) > 0;
+    `
+
+// GetAggregatedData obtém o maior preço e o maior volume diário do instrumento no
+// intervalo [startDate, endDate]. Lê os resumos pré-calculados em daily_summaries; os
+// pregões sem resumo (dados carregados antes do recurso e ainda não recalculados) são
+// calculados diretamente de 'trades' e combinados aos demais. Com 'session', considera
+// apenas as negociações daquele tipo de sessão, sempre a partir de 'trades'.
+func (r *postgresTradeRepository) GetAggregatedData(ctx context.Context, instrumentCode string, startDate, endDate time.Time, session *int) (*entity.AggregatedData, error) {
+	var result *entity.AggregatedData
+	var err error
+	if session != nil {
+		result, err = r.queryAggregatedData(ctx, aggregatedSessionQuery, instrumentCode, startDate, endDate, *session)
+	} else {
+		var missing []time.Time
+		missing, err = r.unsummarizedDates(ctx, instrumentCode, startDate, endDate)
+		if err != nil {
+			return nil, err
+		}
+		if len(missing) == 0 {
+			result, err = r.queryAggregatedData(ctx, AggregatedSummariesQuery, instrumentCode, startDate, endDate)
+		} else {
+			result, err = r.queryAggregatedData(ctx, aggregatedMergedQuery, instrumentCode, startDate, endDate, missing)
+		}
+	}
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, fmt.Errorf("repository: dados não encontrados")
 		}
 		return nil, fmt.Errorf("repository: falha ao obter dados agregados: %w", err)
 	}
 
+	return result, nil
+}
+
+// queryAggregatedData executa uma das consultas de dados agregados. 'extra' são os
+// parâmetros seguintes ao intervalo (ex: a sessão de aggregatedSessionQuery).
+func (r *postgresTradeRepository) queryAggregatedData(ctx context.Context, query, instrumentCode string, startDate, endDate time.Time, extra ...any) (*entity.AggregatedData, error) {
+	result := entity.AggregatedData{InstrumentCode: instrumentCode}
+	args := append([]any{instrumentCode, startDate, endDate}, extra...)
+	err := r.pool.QueryRow(ctx, query, args...).Scan(
+		&result.MaxRangeValue,
+		&result.MaxDailyVolume,
+	)
+	if err != nil {
+		return nil, err
+	}
 	return &result, nil
 }
@@ -0,0 +1,96 @@
+// internal/repository/trade_copy.go
+package repository
+
+import (
+	"context"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// tradeColumns são as colunas gravadas pelo COPY FROM, na mesma ordem de Values().
+var tradeColumns = []string{
+	"trade_date", "instrument_code", "negotiated_price", "negotiated_quantity", "closing_time",
+	"buyer_participant", "seller_participant", "session_type", "trade_id",
+}
+
+// tradeCopySource implementa pgx.CopyFromSource consumindo as negociações
+// diretamente do canal do pipeline, sem materializar lotes em memória.
+//
+// A linha devolvida por Values() é um slice fixo de ponteiros para os campos de
+// 'current'; como o pgx codifica cada linha antes de chamar Next() novamente,
+// o mesmo slice é reaproveitado e nenhum valor é alocado (boxing) por linha.
+type tradeCopySource struct {
+	ctx     context.Context
+	tradeCh <-chan entity.Trade
+	onRow   func(*entity.Trade) // Callback opcional chamado a cada linha consumida (ex: progresso, checksums)
+
+	limit   int  // Máximo de linhas por ciclo de COPY (intervalo de commit)
+	count   int  // Linhas consumidas no ciclo atual
+	drained bool // Indica que o canal foi fechado
+	err     error
+
+	current entity.Trade
+	row     []any
+}
+
+// newTradeCopySource cria uma fonte de COPY que encerra cada ciclo após 'limit' linhas.
+func newTradeCopySource(ctx context.Context, tradeCh <-chan entity.Trade, limit int, onRow func(*entity.Trade)) *tradeCopySource {
+	src := &tradeCopySource{
+		ctx:     ctx,
+		tradeCh: tradeCh,
+		onRow:   onRow,
+		limit:   limit,
+	}
+	src.row = []any{
+		&src.current.TradeDate,
+		&src.current.InstrumentCode,
+		&src.current.NegotiatedPrice,
+		&src.current.NegotiatedQuantity,
+		&src.current.ClosingTime,
+		&src.current.BuyerParticipant,
+		&src.current.SellerParticipant,
+		&src.current.SessionType,
+		&src.current.TradeID,
+	}
+	return src
+}
+
+// reset inicia um novo ciclo de COPY, zerando o contador de linhas.
+func (s *tradeCopySource) reset() {
+	s.count = 0
+}
+
+// Next avança para a próxima negociação do canal. Retorna false ao atingir o
+// intervalo de commit, ao fim do canal ou quando o contexto é cancelado.
+func (s *tradeCopySource) Next() bool {
+	if s.drained || s.err != nil || (s.limit > 0 && s.count >= s.limit) {
+		return false
+	}
+
+	select {
+	case <-s.ctx.Done():
+		s.err = s.ctx.Err()
+		return false
+	case trade, ok := <-s.tradeCh:
+		if !ok {
+			s.drained = true
+			return false
+		}
+		s.current = trade
+		s.count++
+		if s.onRow != nil {
+			s.onRow(&s.current)
+		}
+		return true
+	}
+}
+
+// Values retorna a linha atual como ponteiros para os campos tipados da negociação.
+func (s *tradeCopySource) Values() ([]any, error) {
+	return s.row, nil
+}
+
+// Err retorna o erro que interrompeu a leitura, se houver.
+func (s *tradeCopySource) Err() error {
+	return s.err
+}
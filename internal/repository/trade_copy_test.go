@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// loadSampleTrades lê data/test_sample.txt com um parser mínimo, suficiente para os
+// benchmarks do caminho de gravação (o parser completo fica em internal/ingestion).
+func loadSampleTrades(tb testing.TB) []entity.Trade {
+	tb.Helper()
+	file, err := os.Open("../../data/test_sample.txt")
+	if err != nil {
+		tb.Fatalf("abrir amostra: %v", err)
+	}
+	defer file.Close()
+
+	var trades []entity.Trade
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // Cabeçalho
+	for scanner.Scan() {
+		parts := strings.Split(scanner.Text(), ";")
+		tradeDate, err := time.Parse("2006-01-02", parts[8])
+		if err != nil {
+			tb.Fatalf("data inválida na amostra: %v", err)
+		}
+		price, _ := strconv.ParseFloat(strings.Replace(parts[3], ",", ".", 1), 64)
+		quantity, _ := strconv.Atoi(parts[4])
+		tradeID, _ := strconv.ParseInt(parts[6], 10, 64)
+		session, _ := strconv.Atoi(parts[7])
+		buyer, _ := strconv.Atoi(parts[9])
+		seller, _ := strconv.Atoi(parts[10])
+		trades = append(trades, entity.Trade{
+			TradeDate: tradeDate, InstrumentCode: parts[1], NegotiatedPrice: price, NegotiatedQuantity: quantity,
+			ClosingTime: parts[5], BuyerParticipant: buyer, SellerParticipant: seller, SessionType: session, TradeID: tradeID,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		tb.Fatalf("ler amostra: %v", err)
+	}
+	return trades
+}
+
+// tradeChannel devolve um canal fechado com as negociações, como o pipeline entrega aos workers.
+func tradeChannel(trades []entity.Trade) <-chan entity.Trade {
+	ch := make(chan entity.Trade, len(trades))
+	for _, trade := range trades {
+		ch <- trade
+	}
+	close(ch)
+	return ch
+}
+
+func TestTradeCopySourceMatchesTradesToRows(t *testing.T) {
+	trades := loadSampleTrades(t)
+	want := (&postgresTradeRepository{}).tradesToRows(trades)
+
+	src := newTradeCopySource(context.Background(), tradeChannel(trades), 0, nil)
+	i := 0
+	for src.Next() {
+		values, err := src.Values()
+		if err != nil {
+			t.Fatalf("Values: %v", err)
+		}
+		if len(values) != len(tradeColumns) {
+			t.Fatalf("linha com %d valores, esperado %d colunas", len(values), len(tradeColumns))
+		}
+		for col, ptr := range values {
+			got := reflect.ValueOf(ptr).Elem().Interface()
+			if !reflect.DeepEqual(got, want[i][col]) {
+				t.Errorf("linha %d, coluna %s: got %v, want %v", i, tradeColumns[col], got, want[i][col])
+			}
+		}
+		i++
+	}
+	if err := src.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if i != len(trades) {
+		t.Fatalf("%d linhas lidas, esperado %d", i, len(trades))
+	}
+}
+
+func TestTradeCopySourceLimit(t *testing.T) {
+	trades := loadSampleTrades(t)
+	src := newTradeCopySource(context.Background(), tradeChannel(trades), 40, nil)
+
+	var cycles []int
+	for !src.drained {
+		src.reset()
+		n := 0
+		for src.Next() {
+			n++
+		}
+		cycles = append(cycles, n)
+	}
+	want := []int{40, 40, len(trades) - 80}
+	if !reflect.DeepEqual(cycles, want) {
+		t.Fatalf("ciclos de COPY = %v, esperado %v", cycles, want)
+	}
+}
+
+// sinkRow evita que o compilador descarte as linhas produzidas nos benchmarks.
+var sinkRow []any
+
+// BenchmarkTradesToRows mede o caminho 'batch': cada campo de cada linha é convertido
+// para interface{} antes do COPY.
+func BenchmarkTradesToRows(b *testing.B) {
+	trades := loadSampleTrades(b)
+	repo := &postgresTradeRepository{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rows := repo.tradesToRows(trades)
+		sinkRow = rows[len(rows)-1]
+	}
+	b.ReportMetric(float64(len(trades)*b.N)/b.Elapsed().Seconds(), "rows/s")
+}
+
+// BenchmarkTradeCopySource mede o caminho 'stream': as linhas saem do canal do pipeline
+// pelo slice fixo de ponteiros de Values(). As alocações por operação são as do canal e
+// da fonte, independentes do número de linhas.
+func BenchmarkTradeCopySource(b *testing.B) {
+	trades := loadSampleTrades(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		ch := tradeChannel(trades)
+		b.StartTimer()
+		src := newTradeCopySource(ctx, ch, 0, nil)
+		for src.Next() {
+			sinkRow, _ = src.Values()
+		}
+	}
+	b.ReportMetric(float64(len(trades)*b.N)/b.Elapsed().Seconds(), "rows/s")
+}
+
+// Os benchmarks de gravação repetem a amostra benchSampleCopies vezes para que o modo
+// 'batch' faça várias transações de benchBatchSize linhas, como na ingestão.
+const (
+	benchSampleCopies = 100
+	benchBatchSize    = 1000 // BatchSize padrão do modo 'batch'
+	benchCommitEvery  = 100000
+)
+
+// benchmarkWrite grava as negociações em uma staging nova a cada iteração com write e
+// reporta a vazão em linhas por segundo; a criação e a remoção da staging não entram na
+// medida. Sem TEST_DATABASE_URL, o benchmark é ignorado.
+func benchmarkWrite(b *testing.B, write func(ctx context.Context, repo *postgresTradeRepository, table string, trades []entity.Trade) error) {
+	repo := &postgresTradeRepository{pool: testDatabase(b)}
+	ctx := context.Background()
+	sample := loadSampleTrades(b)
+	trades := make([]entity.Trade, 0, len(sample)*benchSampleCopies)
+	for i := 0; i < benchSampleCopies; i++ {
+		trades = append(trades, sample...)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		table, err := repo.CreateStagingTable(ctx)
+		if err != nil {
+			b.Fatalf("CreateStagingTable: %v", err)
+		}
+		b.StartTimer()
+		if err := write(ctx, repo, table, trades); err != nil {
+			b.Fatalf("gravar negociações: %v", err)
+		}
+		b.StopTimer()
+		if err := repo.DropStagingTable(ctx, table); err != nil {
+			b.Fatalf("DropStagingTable: %v", err)
+		}
+		b.StartTimer()
+	}
+	b.ReportMetric(float64(len(trades)*b.N)/b.Elapsed().Seconds(), "rows/s")
+}
+
+// BenchmarkSaveTradesBatch grava como o modo 'batch': uma transação com COPY por lote
+// de benchBatchSize linhas (SaveTrades).
+func BenchmarkSaveTradesBatch(b *testing.B) {
+	benchmarkWrite(b, func(ctx context.Context, repo *postgresTradeRepository, table string, trades []entity.Trade) error {
+		for start := 0; start < len(trades); start += benchBatchSize {
+			end := min(start+benchBatchSize, len(trades))
+			if err := repo.SaveTrades(ctx, table, trades[start:end]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// BenchmarkStreamTrades grava como o modo 'stream': um COPY contínuo alimentado pelo
+// canal, com commit a cada benchCommitEvery linhas (StreamTrades). O preenchimento do
+// canal entra na medida, como a leitura do arquivo na ingestão.
+func BenchmarkStreamTrades(b *testing.B) {
+	benchmarkWrite(b, func(ctx context.Context, repo *postgresTradeRepository, table string, trades []entity.Trade) error {
+		_, err := repo.StreamTrades(ctx, table, tradeChannel(trades), benchCommitEvery, nil)
+		return err
+	})
+}
@@ -0,0 +1,185 @@
+// internal/repository/trade_id.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// CheckTradeIDs verifica, nas datas informadas da tabela 'table' ('trades' ou uma
+// staging), a sequência de identificadores de negócio de cada instrumento, que cresce de
+// 'step' em 'step'. Só são lacunas os saltos entre dois identificadores presentes: um
+// arquivo pode começar no meio da sequência (ex: um recorte do pregão), então os
+// identificadores anteriores ao primeiro não contam como ausentes. Identificadores que
+// não são múltiplos de 'step' ficam fora da sequência e são apontados como misaligned,
+// um registro por instrumento. Negociações sem identificador (trade_id = 0) são
+// ignoradas. Retorna uma verificação por data, na ordem recebida, sem MissingPct.
+func (r *postgresTradeRepository) CheckTradeIDs(ctx context.Context, table string, tradeDates []time.Time, step int64) ([]entity.TradeIDCheck, error) {
+	checks := make([]entity.TradeIDCheck, len(tradeDates))
+	byDate := make(map[string]*entity.TradeIDCheck, len(tradeDates))
+	for i, date := range tradeDates {
+		checks[i].TradeDate = date
+		checks[i].Issues = []entity.TradeIDIssue{}
+		byDate[date.Format("2006-01-02")] = &checks[i]
+	}
+	if len(tradeDates) == 0 {
+		return checks, nil
+	}
+
+	source := pgx.Identifier{table}.Sanitize()
+
+	countQuery := fmt.Sprintf(`
+        SELECT
+            trade_date, COUNT(*)
+        FROM %s
+        WHERE
+            trade_date = ANY($1) AND trade_id > 0
+        GROUP BY
+            trade_date`, source)
+
+	rows, err := r.pool.Query(ctx, countQuery, tradeDates)
+	if err != nil {
+		return nil, fmt.Errorf("repository: falha ao contar identificadores de negócio: %w", err)
+	}
+	for rows.Next() {
+		var tradeDate time.Time
+		var count int64
+		if err := rows.Scan(&tradeDate, &count); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("repository: falha ao ler contagem de identificadores: %w", err)
+		}
+		if check, ok := byDate[tradeDate.Format("2006-01-02")]; ok {
+			check.Trades = count
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: falha ao iterar contagem de identificadores: %w", err)
+	}
+
+	// Cada salto maior que 'step' em relação ao identificador anterior é uma lacuna (o
+	// primeiro não tem anterior e nunca gera lacuna); identificadores repetidos não
+	// geram salto e são contados à parte.
+	issueQuery := fmt.Sprintf(`
+        WITH ordered AS (
+            SELECT
+                trade_date, instrument_code, trade_id,
+                LAG(trade_id) OVER (
+                    PARTITION BY trade_date, instrument_code ORDER BY trade_id
+                ) AS previous_id
+            FROM %s
+            WHERE
+                trade_date = ANY($1) AND trade_id > 0 AND trade_id %% $2::BIGINT = 0
+        )
+        SELECT
+            trade_date, instrument_code, '%s', previous_id + $2::BIGINT, trade_id - $2::BIGINT,
+            (trade_id - previous_id) / $2::BIGINT - 1
+        FROM
+            ordered
+        WHERE
+            previous_id IS NOT NULL AND (trade_id - previous_id) / $2::BIGINT > 1
+        UNION ALL
+        SELECT
+            trade_date, instrument_code, '%s', trade_id, trade_id, COUNT(*) - 1
+        FROM
+            %s
+        WHERE
+            trade_date = ANY($1) AND trade_id > 0 AND trade_id %% $2::BIGINT = 0
+        GROUP BY
+            trade_date, instrument_code, trade_id
+        HAVING
+            COUNT(*) > 1
+        UNION ALL
+        SELECT
+            trade_date, instrument_code, '%s', MIN(trade_id), MAX(trade_id), COUNT(*)
+        FROM
+            %s
+        WHERE
+            trade_date = ANY($1) AND trade_id > 0 AND trade_id %% $2::BIGINT <> 0
+        GROUP BY
+            trade_date, instrument_code
+        ORDER BY
+            1, 2, 4`, source, entity.TradeIDGap, entity.TradeIDDuplicate, source, entity.TradeIDMisaligned, source)
+
+	rows, err = r.pool.Query(ctx, issueQuery, tradeDates, step)
+	if err != nil {
+		return nil, fmt.Errorf("repository: falha ao verificar identificadores de negócio: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var issue entity.TradeIDIssue
+		if err := rows.Scan(&issue.TradeDate, &issue.InstrumentCode, &issue.Kind, &issue.FirstID, &issue.LastID, &issue.Count); err != nil {
+			return nil, fmt.Errorf("repository: falha ao ler falha de identificador: %w", err)
+		}
+		if check, ok := byDate[issue.TradeDate.Format("2006-01-02")]; ok {
+			check.Add(issue)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: falha ao iterar falhas de identificador: %w", err)
+	}
+
+	return checks, nil
+}
+
+// ReplaceTradeIDIssues substitui, em uma única transação, as falhas de identificador
+// gravadas para as datas verificadas pelas falhas de 'checks'.
+func (r *postgresTradeRepository) ReplaceTradeIDIssues(ctx context.Context, checks []entity.TradeIDCheck) error {
+	if len(checks) == 0 {
+		return nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("repository: falha ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := replaceTradeIDIssues(ctx, tx, checks); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("repository: falha ao confirmar falhas de identificador: %w", err)
+	}
+
+	return nil
+}
+
+// replaceTradeIDIssues substitui, dentro da transação, as falhas de identificador das
+// datas verificadas pelas falhas de 'checks'.
+func replaceTradeIDIssues(ctx context.Context, tx pgx.Tx, checks []entity.TradeIDCheck) error {
+	if len(checks) == 0 {
+		return nil
+	}
+
+	tradeDates := make([]time.Time, len(checks))
+	var issues [][]any
+	for i, check := range checks {
+		tradeDates[i] = check.TradeDate
+		for _, issue := range check.Issues {
+			issues = append(issues, []any{
+				issue.TradeDate, issue.InstrumentCode, issue.Kind, issue.FirstID, issue.LastID, issue.Count,
+			})
+		}
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM trade_id_issues WHERE trade_date = ANY($1)", tradeDates); err != nil {
+		return fmt.Errorf("repository: falha ao remover falhas de identificador anteriores: %w", err)
+	}
+	if len(issues) > 0 {
+		_, err := tx.CopyFrom(ctx, pgx.Identifier{"trade_id_issues"},
+			[]string{"trade_date", "instrument_code", "kind", "first_id", "last_id", "count"},
+			pgx.CopyFromRows(issues))
+		if err != nil {
+			return fmt.Errorf("repository: falha ao gravar falhas de identificador: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/migrate"
+)
+
+// testSchema é o schema isolado dos testes de integração, recriado a cada teste.
+const testSchema = "b3_test"
+
+// testDatabase conecta ao PostgreSQL de TEST_DATABASE_URL com o search_path em
+// testSchema, recriado com as migrações embutidas. Sem a variável, o teste é ignorado.
+func testDatabase(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL não definida; teste de integração ignorado")
+	}
+
+	ctx := context.Background()
+	config, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		t.Fatalf("TEST_DATABASE_URL inválida: %v", err)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = testSchema
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("conectar ao banco de testes: %v", err)
+	}
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), "DROP SCHEMA IF EXISTS "+testSchema+" CASCADE")
+		pool.Close()
+	})
+
+	for _, stmt := range []string{"DROP SCHEMA IF EXISTS " + testSchema + " CASCADE", "CREATE SCHEMA " + testSchema} {
+		if _, err := pool.Exec(ctx, stmt); err != nil {
+			t.Fatalf("preparar schema de testes: %v", err)
+		}
+	}
+	migrator, err := migrate.NewEmbedded(pool)
+	if err != nil {
+		t.Fatalf("carregar migrações: %v", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("aplicar migrações: %v", err)
+	}
+	return pool
+}
+
+// checkStagedTradeIDs grava as negociações em uma staging e verifica a data informada.
+func checkStagedTradeIDs(t *testing.T, repo *postgresTradeRepository, trades []entity.Trade, date time.Time) entity.TradeIDCheck {
+	t.Helper()
+	ctx := context.Background()
+	table, err := repo.CreateStagingTable(ctx)
+	if err != nil {
+		t.Fatalf("CreateStagingTable: %v", err)
+	}
+	defer repo.DropStagingTable(ctx, table)
+
+	if err := repo.SaveTrades(ctx, table, trades); err != nil {
+		t.Fatalf("SaveTrades: %v", err)
+	}
+	checks, err := repo.CheckTradeIDs(ctx, table, []time.Time{date}, 10)
+	if err != nil {
+		t.Fatalf("CheckTradeIDs: %v", err)
+	}
+	if len(checks) != 1 {
+		t.Fatalf("%d verificações, esperado 1", len(checks))
+	}
+	return checks[0]
+}
+
+// Na amostra, vários instrumentos começam no meio da sequência (CCMX25 em 3800, BGIV25 e
+// ICFZ25 em 130), como em um recorte do arquivo do pregão. Sem lacunas internas, nada
+// pode ser apontado como ausente.
+func TestCheckTradeIDsSample(t *testing.T) {
+	repo := &postgresTradeRepository{pool: testDatabase(t)}
+	trades := loadSampleTrades(t)
+	date := trades[0].TradeDate
+
+	check := checkStagedTradeIDs(t, repo, trades, date)
+	want := entity.TradeIDCheck{TradeDate: date, Trades: int64(len(trades)), Issues: []entity.TradeIDIssue{}}
+	if !reflect.DeepEqual(check, want) {
+		t.Errorf("amostra completa: %+v, esperado %+v", check, want)
+	}
+
+	// Variante com uma lacuna interna (BITQ25 50 e 60), um início de recorte (DOLV25 10,
+	// que não é lacuna), uma repetição (DI1F26 30) e um identificador fora do incremento
+	// (INDV25 125).
+	var modified []entity.Trade
+	for _, trade := range trades {
+		switch {
+		case trade.InstrumentCode == "BITQ25" && (trade.TradeID == 50 || trade.TradeID == 60):
+			continue
+		case trade.InstrumentCode == "DOLV25" && trade.TradeID == 10:
+			continue
+		case trade.InstrumentCode == "DI1F26" && trade.TradeID == 30:
+			modified = append(modified, trade)
+		case trade.InstrumentCode == "INDV25" && trade.TradeID == 120:
+			extra := trade
+			extra.TradeID = 125
+			modified = append(modified, extra)
+		}
+		modified = append(modified, trade)
+	}
+
+	check = checkStagedTradeIDs(t, repo, modified, date)
+	want = entity.TradeIDCheck{
+		TradeDate: date, Trades: int64(len(modified)), Missing: 2, Duplicates: 1, Misaligned: 1,
+		Issues: []entity.TradeIDIssue{
+			{TradeDate: date, InstrumentCode: "BITQ25", Kind: entity.TradeIDGap, FirstID: 50, LastID: 60, Count: 2},
+			{TradeDate: date, InstrumentCode: "DI1F26", Kind: entity.TradeIDDuplicate, FirstID: 30, LastID: 30, Count: 1},
+			{TradeDate: date, InstrumentCode: "INDV25", Kind: entity.TradeIDMisaligned, FirstID: 125, LastID: 125, Count: 1},
+		},
+	}
+	if !reflect.DeepEqual(check, want) {
+		t.Errorf("amostra com falhas: %+v, esperado %+v", check, want)
+	}
+}
@@ -0,0 +1,103 @@
+// internal/repository/trade_load.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// GetLoadTotals calcula, por data e ticker, os totais de 'trades' usados na reconciliação
+// de cargas. As chaves do primeiro nível usam o formato YYYY-MM-DD.
+func (r *postgresTradeRepository) GetLoadTotals(ctx context.Context, tradeDates []time.Time) (map[string]map[string]entity.LoadTotals, error) {
+	query := fmt.Sprintf(`
+        SELECT
+            trade_date,
+            instrument_code,
+            COUNT(*),
+            COALESCE(SUM(negotiated_quantity), 0)::BIGINT,
+            COALESCE(SUM(ROUND(negotiated_price * %d) * negotiated_quantity), 0)::BIGINT
+        FROM
+            trades
+        WHERE
+            trade_date = ANY($1)
+        GROUP BY
+            trade_date, instrument_code;
+    `, entity.PriceScale)
+
+	rows, err := r.pool.Query(ctx, query, tradeDates)
+	if err != nil {
+		return nil, fmt.Errorf("repository: falha ao calcular totais das negociações: %w", err)
+	}
+	defer rows.Close()
+
+	totals := make(map[string]map[string]entity.LoadTotals, len(tradeDates))
+	for rows.Next() {
+		var tradeDate time.Time
+		var instrumentCode string
+		var t entity.LoadTotals
+		if err := rows.Scan(&tradeDate, &instrumentCode, &t.Rows, &t.Quantity, &t.NotionalTicks); err != nil {
+			return nil, fmt.Errorf("repository: falha ao ler totais das negociações: %w", err)
+		}
+		key := tradeDate.Format("2006-01-02")
+		if totals[key] == nil {
+			totals[key] = make(map[string]entity.LoadTotals)
+		}
+		totals[key][instrumentCode] = t
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: falha ao iterar totais das negociações: %w", err)
+	}
+
+	return totals, nil
+}
+
+// RecordTradeLoads registra a carga de fileName nas datas informadas, com o número de
+// negociações de cada data (tradeCounts[i] corresponde a tradeDates[i]). Uma carga nova
+// descarta a reconciliação anterior da data.
+func (r *postgresTradeRepository) RecordTradeLoads(ctx context.Context, fileName string, tradeDates []time.Time, tradeCounts []int64) error {
+	query := `
+        INSERT INTO trade_loads (trade_date, file_name, trade_count, loaded_at)
+        SELECT d, $2, n, NOW()
+        FROM unnest($1::DATE[], $3::BIGINT[]) AS t(d, n)
+        ON CONFLICT (trade_date) DO UPDATE SET
+            file_name = EXCLUDED.file_name,
+            trade_count = EXCLUDED.trade_count,
+            loaded_at = EXCLUDED.loaded_at,
+            verified_file = NULL,
+            verified_count = NULL,
+            verified_at = NULL,
+            discrepancies = NULL,
+            rejected_lines = NULL;
+    `
+	if _, err := r.pool.Exec(ctx, query, tradeDates, fileName, tradeCounts); err != nil {
+		return fmt.Errorf("repository: falha ao registrar carga: %w", err)
+	}
+	return nil
+}
+
+// RecordLoadVerification registra a reconciliação de fileName nas datas informadas, com
+// o número de negociações no arquivo e de tickers divergentes de cada data, além das
+// linhas do arquivo rejeitadas pelo reader. Datas sem divergências ficam marcadas como
+// verificadas quando nenhuma linha foi rejeitada. fileName e as contagens ficam em
+// verified_file e verified_count: file_name e trade_count continuam sendo os da carga, e
+// datas sem carga registrada (carregadas antes de trade_loads) são incluídas sem eles.
+func (r *postgresTradeRepository) RecordLoadVerification(ctx context.Context, fileName string, tradeDates []time.Time, tradeCounts []int64, discrepancies []int32, rejected int64) error {
+	query := `
+        INSERT INTO trade_loads (trade_date, verified_file, verified_count, verified_at, discrepancies, rejected_lines)
+        SELECT d, $2, n, CASE WHEN x = 0 AND $5::BIGINT = 0 THEN NOW() END, x, $5::BIGINT
+        FROM unnest($1::DATE[], $3::BIGINT[], $4::INT[]) AS t(d, n, x)
+        ON CONFLICT (trade_date) DO UPDATE SET
+            verified_file = EXCLUDED.verified_file,
+            verified_count = EXCLUDED.verified_count,
+            verified_at = EXCLUDED.verified_at,
+            discrepancies = EXCLUDED.discrepancies,
+            rejected_lines = EXCLUDED.rejected_lines;
+    `
+	if _, err := r.pool.Exec(ctx, query, tradeDates, fileName, tradeCounts, discrepancies, rejected); err != nil {
+		return fmt.Errorf("repository: falha ao registrar reconciliação: %w", err)
+	}
+	return nil
+}
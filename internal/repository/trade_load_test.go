@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// tradeLoad é uma linha de trade_loads; os campos opcionais ficam nulos quando ausentes.
+type tradeLoad struct {
+	FileName      *string
+	TradeCount    *int64
+	VerifiedFile  *string
+	VerifiedCount *int64
+	Verified      bool
+}
+
+func readTradeLoad(t *testing.T, repo *postgresTradeRepository, date time.Time) tradeLoad {
+	t.Helper()
+	var load tradeLoad
+	err := repo.pool.QueryRow(context.Background(), `
+        SELECT file_name, trade_count, verified_file, verified_count, verified_at IS NOT NULL
+        FROM trade_loads WHERE trade_date = $1`, date).
+		Scan(&load.FileName, &load.TradeCount, &load.VerifiedFile, &load.VerifiedCount, &load.Verified)
+	if err != nil {
+		t.Fatalf("ler trade_loads de %s: %v", date.Format("2006-01-02"), err)
+	}
+	return load
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return "<nulo>"
+	}
+	return *s
+}
+
+// A reconciliação de outro arquivo não altera a procedência da carga, e uma data sem
+// carga registrada não ganha file_name.
+func TestRecordLoadVerificationKeepsLoad(t *testing.T) {
+	repo := &postgresTradeRepository{pool: testDatabase(t)}
+	ctx := context.Background()
+	loaded := time.Date(2025, time.August, 28, 0, 0, 0, 0, time.UTC)
+	unloaded := time.Date(2025, time.August, 29, 0, 0, 0, 0, time.UTC)
+
+	if err := repo.RecordTradeLoads(ctx, "carga.txt", []time.Time{loaded}, []int64{100}); err != nil {
+		t.Fatalf("RecordTradeLoads: %v", err)
+	}
+	err := repo.RecordLoadVerification(ctx, "outro.txt", []time.Time{loaded, unloaded}, []int64{90, 50}, []int32{0, 0}, 0)
+	if err != nil {
+		t.Fatalf("RecordLoadVerification: %v", err)
+	}
+
+	load := readTradeLoad(t, repo, loaded)
+	if stringValue(load.FileName) != "carga.txt" || load.TradeCount == nil || *load.TradeCount != 100 {
+		t.Errorf("carga = %s/%v, esperado carga.txt/100", stringValue(load.FileName), load.TradeCount)
+	}
+	if stringValue(load.VerifiedFile) != "outro.txt" || load.VerifiedCount == nil || *load.VerifiedCount != 90 || !load.Verified {
+		t.Errorf("reconciliação = %s/%v (verificada: %v), esperado outro.txt/90 verificada",
+			stringValue(load.VerifiedFile), load.VerifiedCount, load.Verified)
+	}
+
+	load = readTradeLoad(t, repo, unloaded)
+	if load.FileName != nil || load.TradeCount != nil || stringValue(load.VerifiedFile) != "outro.txt" {
+		t.Errorf("data sem carga = %s/%v/%s, esperado <nulo>/<nulo>/outro.txt",
+			stringValue(load.FileName), load.TradeCount, stringValue(load.VerifiedFile))
+	}
+
+	// Linhas rejeitadas impedem a verificação; uma carga nova descarta a reconciliação.
+	if err := repo.RecordLoadVerification(ctx, "carga.txt", []time.Time{loaded}, []int64{100}, []int32{0}, 1); err != nil {
+		t.Fatalf("RecordLoadVerification: %v", err)
+	}
+	if readTradeLoad(t, repo, loaded).Verified {
+		t.Error("data verificada com linha rejeitada")
+	}
+	if err := repo.RecordTradeLoads(ctx, "nova.txt", []time.Time{loaded}, []int64{110}); err != nil {
+		t.Fatalf("RecordTradeLoads: %v", err)
+	}
+	if load := readTradeLoad(t, repo, loaded); load.VerifiedFile != nil || load.Verified {
+		t.Errorf("reconciliação mantida após nova carga: %s", stringValue(load.VerifiedFile))
+	}
+}
@@ -0,0 +1,69 @@
+// internal/repository/trade_query.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// TradeQuery filtra as negociações lidas por ScanTrades.
+type TradeQuery struct {
+	InstrumentCode string
+	From, To       time.Time // Intervalo de datas [From, To]
+	FromTime       string    // Horário inicial "HHMMSSmmm" (inclusivo); vazio não limita
+	ToTime         string    // Horário final "HHMMSSmmm" (exclusivo); vazio não limita
+	Session        *int      // Tipo de sessão (entity.SessionRegular, ...); nil considera todas
+}
+
+// ScanTrades percorre as negociações que atendem ao filtro em ordem de data, horário
+// de fechamento e preço (a mesma ordem usada para abertura e fechamento dos resumos),
+// chamando fn para cada uma sem carregar o resultado inteiro em memória. O ponteiro
+// recebido por fn é reutilizado entre chamadas. Um erro retornado por fn interrompe a leitura.
+func (r *postgresTradeRepository) ScanTrades(ctx context.Context, q TradeQuery, fn func(*entity.Trade) error) error {
+	query := `
+        SELECT
+            trade_date, negotiated_price, negotiated_quantity, closing_time
+        FROM
+            trades
+        WHERE
+            instrument_code = $1
+            AND trade_date >= $2 AND trade_date <= $3
+            AND ($4::TEXT IS NULL OR closing_time >= $4)
+            AND ($5::TEXT IS NULL OR closing_time < $5)
+            AND ($6::SMALLINT IS NULL OR session_type = $6)
+        ORDER BY
+            trade_date, closing_time, negotiated_price;
+    `
+
+	rows, err := r.pool.Query(ctx, query, q.InstrumentCode, q.From, q.To, nullableText(q.FromTime), nullableText(q.ToTime), q.Session)
+	if err != nil {
+		return fmt.Errorf("repository: falha ao consultar negociações: %w", err)
+	}
+	defer rows.Close()
+
+	trade := entity.Trade{InstrumentCode: q.InstrumentCode}
+	for rows.Next() {
+		if err := rows.Scan(&trade.TradeDate, &trade.NegotiatedPrice, &trade.NegotiatedQuantity, &trade.ClosingTime); err != nil {
+			return fmt.Errorf("repository: falha ao ler negociação: %w", err)
+		}
+		if err := fn(&trade); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("repository: falha ao iterar negociações: %w", err)
+	}
+
+	return nil
+}
+
+// nullableText converte uma string vazia em NULL para os filtros opcionais das consultas.
+func nullableText(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
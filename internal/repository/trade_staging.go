@@ -0,0 +1,134 @@
+// internal/repository/trade_staging.go
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// TradesTable é a tabela definitiva de negociações lida pela API.
+const TradesTable = "trades"
+
+// swapLockKey identifica o advisory lock que serializa as trocas de datas em 'trades',
+// evitando que duas ingestões simultâneas da mesma data dupliquem registros.
+const swapLockKey = 0x42335452 // "B3TR"
+
+// CreateStagingTable cria uma tabela UNLOGGED exclusiva para a carga atual, com as
+// mesmas colunas de dados de 'trades'. Por ser UNLOGGED, a escrita não gera WAL e a
+// tabela é descartada ao fim da ingestão.
+func (r *postgresTradeRepository) CreateStagingTable(ctx context.Context) (string, error) {
+	table := fmt.Sprintf("trades_staging_%d", time.Now().UnixNano())
+
+	query := fmt.Sprintf(
+		"CREATE UNLOGGED TABLE %s AS SELECT %s FROM %s WITH NO DATA",
+		pgx.Identifier{table}.Sanitize(), strings.Join(tradeColumns, ", "), pgx.Identifier{TradesTable}.Sanitize(),
+	)
+	if _, err := r.pool.Exec(ctx, query); err != nil {
+		return "", fmt.Errorf("repository: falha ao criar tabela de staging: %w", err)
+	}
+	return table, nil
+}
+
+// DropStagingTable remove a tabela de staging criada por CreateStagingTable.
+func (r *postgresTradeRepository) DropStagingTable(ctx context.Context, table string) error {
+	query := fmt.Sprintf("DROP TABLE IF EXISTS %s", pgx.Identifier{table}.Sanitize())
+	if _, err := r.pool.Exec(ctx, query); err != nil {
+		return fmt.Errorf("repository: falha ao remover tabela de staging %s: %w", table, err)
+	}
+	return nil
+}
+
+// GetStagingChecksums calcula, por data de negociação, a contagem de linhas e as
+// somas de quantidade e de preço (em décimos de milésimo) gravadas na staging.
+// As chaves do mapa usam o formato YYYY-MM-DD.
+func (r *postgresTradeRepository) GetStagingChecksums(ctx context.Context, table string) (map[string]entity.TradeChecksum, error) {
+	query := fmt.Sprintf(`
+        SELECT
+            trade_date,
+            COUNT(*),
+            COALESCE(SUM(negotiated_quantity), 0)::BIGINT,
+            COALESCE(SUM(ROUND(negotiated_price * %d)), 0)::BIGINT
+        FROM %s
+        GROUP BY trade_date`, entity.PriceScale, pgx.Identifier{table}.Sanitize())
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("repository: falha ao calcular checksums da staging: %w", err)
+	}
+	defer rows.Close()
+
+	checksums := make(map[string]entity.TradeChecksum)
+	for rows.Next() {
+		var tradeDate time.Time
+		var checksum entity.TradeChecksum
+		if err := rows.Scan(&tradeDate, &checksum.Rows, &checksum.Quantity, &checksum.PriceTicks); err != nil {
+			return nil, fmt.Errorf("repository: falha ao ler checksums da staging: %w", err)
+		}
+		checksums[tradeDate.Format("2006-01-02")] = checksum
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: falha ao iterar checksums da staging: %w", err)
+	}
+
+	return checksums, nil
+}
+
+// ReplaceTradeDates substitui, em uma única transação, todas as negociações das
+// datas informadas pelo conteúdo da staging, junto com os resumos diários e as falhas
+// de identificador (trade_id_issues) dessas datas. As marcações de outliers das datas
+// são descartadas, pois se referem às negociações substituídas; a detecção é refeita à
+// parte (DetectOutliers). Leitores concorrentes enxergam o dia anterior completo ou o
+// novo dia completo, nunca um estado intermediário.
+func (r *postgresTradeRepository) ReplaceTradeDates(ctx context.Context, table string, tradeDates []time.Time, summaries []entity.DailySummary, idChecks []entity.TradeIDCheck) error {
+	if len(tradeDates) == 0 {
+		return nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("repository: falha ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", swapLockKey); err != nil {
+		return fmt.Errorf("repository: falha ao obter lock de troca: %w", err)
+	}
+
+	// Garante que as partições mensais das datas carregadas existam antes da inserção.
+	if _, err := tx.Exec(ctx, "SELECT ensure_trades_partition(d) FROM unnest($1::DATE[]) AS d", tradeDates); err != nil {
+		return fmt.Errorf("repository: falha ao criar partições de trades: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM trades WHERE trade_date = ANY($1)", tradeDates); err != nil {
+		return fmt.Errorf("repository: falha ao remover negociações anteriores: %w", err)
+	}
+
+	columns := strings.Join(tradeColumns, ", ")
+	insert := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s",
+		pgx.Identifier{TradesTable}.Sanitize(), columns, columns, pgx.Identifier{table}.Sanitize())
+	if _, err := tx.Exec(ctx, insert); err != nil {
+		return fmt.Errorf("repository: falha ao copiar negociações da staging: %w", err)
+	}
+
+	if err := replaceDailySummaries(ctx, tx, tradeDates, summaries); err != nil {
+		return err
+	}
+	if err := replaceTradeIDIssues(ctx, tx, idChecks); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM trade_outliers WHERE trade_date = ANY($1)", tradeDates); err != nil {
+		return fmt.Errorf("repository: falha ao remover outliers anteriores: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("repository: falha ao fazer commit da troca de datas: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// replaceSampleDate grava as negociações da amostra em uma staging e troca a data delas.
+func replaceSampleDate(t *testing.T, repo *postgresTradeRepository) time.Time {
+	t.Helper()
+	ctx := context.Background()
+	trades := loadSampleTrades(t)
+	table, err := repo.CreateStagingTable(ctx)
+	if err != nil {
+		t.Fatalf("CreateStagingTable: %v", err)
+	}
+	defer repo.DropStagingTable(ctx, table)
+
+	if err := repo.SaveTrades(ctx, table, trades); err != nil {
+		t.Fatalf("SaveTrades: %v", err)
+	}
+	date := trades[0].TradeDate
+	if err := repo.ReplaceTradeDates(ctx, table, []time.Time{date}, nil, nil); err != nil {
+		t.Fatalf("ReplaceTradeDates: %v", err)
+	}
+	return date
+}
+
+// Uma nova carga descarta as marcações de outliers da data, que se referem às
+// negociações substituídas.
+func TestReplaceTradeDatesDropsOutliers(t *testing.T) {
+	repo := &postgresTradeRepository{pool: testDatabase(t)}
+	ctx := context.Background()
+	date := replaceSampleDate(t, repo)
+
+	_, err := repo.pool.Exec(ctx, `
+        INSERT INTO trade_outliers (trade_date, instrument_code, closing_time, negotiated_price, negotiated_quantity, kind, score, reference)
+        VALUES ($1, 'PETR4', '100000000', 30, 1000, 'block_quantity', 10, 100)`, date)
+	if err != nil {
+		t.Fatalf("gravar outlier: %v", err)
+	}
+
+	replaceSampleDate(t, repo)
+	outliers, err := repo.ListOutliers(ctx, OutlierQuery{From: date, To: date, Limit: 10})
+	if err != nil {
+		t.Fatalf("ListOutliers: %v", err)
+	}
+	if len(outliers) != 0 {
+		t.Errorf("%d outlier(s) mantido(s) após a nova carga, esperado 0", len(outliers))
+	}
+}
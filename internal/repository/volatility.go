@@ -0,0 +1,72 @@
+// internal/repository/volatility.go
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// GetIntradayVariance calcula, por pregão, a variância realizada das negociações que
+// atendem ao filtro: a soma dos quadrados dos log-retornos entre os fechamentos de
+// barras consecutivas de 'minutes' minutos (a partir de closing_time). Barras sem
+// negociações são ignoradas, e o primeiro retorno de cada dia parte da primeira barra.
+func (r *postgresTradeRepository) GetIntradayVariance(ctx context.Context, q TradeQuery, minutes int) ([]entity.IntradayVariance, error) {
+	query := `
+        WITH bars AS (
+            SELECT
+                trade_date,
+                (SUBSTRING(closing_time, 1, 2)::INT * 60 + SUBSTRING(closing_time, 3, 2)::INT) / $7 AS bar,
+                (ARRAY_AGG(negotiated_price ORDER BY closing_time DESC, negotiated_price DESC))[1] AS close_price
+            FROM
+                trades
+            WHERE
+                instrument_code = $1
+                AND trade_date >= $2 AND trade_date <= $3
+                AND ($4::TEXT IS NULL OR closing_time >= $4)
+                AND ($5::TEXT IS NULL OR closing_time < $5)
+                AND ($6::SMALLINT IS NULL OR session_type = $6)
+                AND negotiated_price > 0
+            GROUP BY
+                trade_date, bar
+        ),
+        returns AS (
+            SELECT
+                trade_date,
+                LN(close_price / LAG(close_price) OVER (PARTITION BY trade_date ORDER BY bar)) AS log_return
+            FROM
+                bars
+        )
+        SELECT
+            trade_date,
+            COALESCE(SUM(log_return * log_return), 0)::FLOAT8,
+            COUNT(*)
+        FROM
+            returns
+        GROUP BY
+            trade_date
+        ORDER BY
+            trade_date;
+    `
+
+	rows, err := r.pool.Query(ctx, query, q.InstrumentCode, q.From, q.To, nullableText(q.FromTime), nullableText(q.ToTime), q.Session, minutes)
+	if err != nil {
+		return nil, fmt.Errorf("repository: falha ao calcular variância intradiária: %w", err)
+	}
+	defer rows.Close()
+
+	var days []entity.IntradayVariance
+	for rows.Next() {
+		var d entity.IntradayVariance
+		if err := rows.Scan(&d.TradeDate, &d.Variance, &d.Bars); err != nil {
+			return nil, fmt.Errorf("repository: falha ao ler variância intradiária: %w", err)
+		}
+		days = append(days, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: falha ao iterar variância intradiária: %w", err)
+	}
+
+	return days, nil
+}
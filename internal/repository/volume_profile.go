@@ -0,0 +1,53 @@
+// internal/repository/volume_profile.go
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// GetPriceLevels agrupa por preço as negociações que atendem ao filtro, em ordem
+// crescente de preço. O agrupamento em faixas fica a cargo do chamador.
+func (r *postgresTradeRepository) GetPriceLevels(ctx context.Context, q TradeQuery) ([]entity.PriceLevel, error) {
+	query := `
+        SELECT
+            negotiated_price,
+            SUM(negotiated_quantity)::BIGINT,
+            SUM(negotiated_price * negotiated_quantity)::FLOAT8,
+            COUNT(*)
+        FROM
+            trades
+        WHERE
+            instrument_code = $1
+            AND trade_date >= $2 AND trade_date <= $3
+            AND ($4::TEXT IS NULL OR closing_time >= $4)
+            AND ($5::TEXT IS NULL OR closing_time < $5)
+            AND ($6::SMALLINT IS NULL OR session_type = $6)
+        GROUP BY
+            negotiated_price
+        ORDER BY
+            negotiated_price;
+    `
+
+	rows, err := r.pool.Query(ctx, query, q.InstrumentCode, q.From, q.To, nullableText(q.FromTime), nullableText(q.ToTime), q.Session)
+	if err != nil {
+		return nil, fmt.Errorf("repository: falha ao consultar volume por preço: %w", err)
+	}
+	defer rows.Close()
+
+	levels := []entity.PriceLevel{}
+	for rows.Next() {
+		var l entity.PriceLevel
+		if err := rows.Scan(&l.Price, &l.Volume, &l.FinancialVolume, &l.TradeCount); err != nil {
+			return nil, fmt.Errorf("repository: falha ao ler volume por preço: %w", err)
+		}
+		levels = append(levels, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repository: falha ao iterar volume por preço: %w", err)
+	}
+
+	return levels, nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+)
+
+// MaxBatchTickers é o maior número de instrumentos aceito em uma consulta em lote.
+const MaxBatchTickers = 200
+
+// AggregatedRequest é um item de RetrieveAggregatedDataBatch. Datas vazias usam as
+// mesmas regras de RetrieveAggregatedData.
+type AggregatedRequest struct {
+	Ticker     string `json:"ticker"`
+	DataInicio string `json:"data_inicio,omitempty"`
+	DataFim    string `json:"data_fim,omitempty"`
+}
+
+// RetrieveAggregatedDataBatch obtém os dados agregados de vários instrumentos com uma
+// única ida ao repositório. O resultado segue a ordem de 'requests'; itens sem dados ou
+// com datas inválidas são marcados individualmente em vez de falhar o lote inteiro.
+func (s *tradeServiceImpl) RetrieveAggregatedDataBatch(ctx context.Context, requests []AggregatedRequest, sessionStr string) ([]entity.AggregatedBatchItem, error) {
+	switch {
+	case len(requests) == 0:
+		return nil, invalidParameter("informe ao menos um ticker")
+	case len(requests) > MaxBatchTickers:
+		return nil, invalidParameter("no máximo %d tickers por consulta, recebidos %d", MaxBatchTickers, len(requests))
+	}
+	session, err := parseSession(sessionStr)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]entity.AggregatedBatchItem, len(requests))
+	queries := make([]repository.AggregatedQuery, 0, len(requests))
+	positions := make([]int, 0, len(requests)) // Posição em 'items' de cada consulta válida
+
+	for i, request := range requests {
+		ticker := strings.TrimSpace(request.Ticker)
+		items[i].InstrumentCode = ticker
+		if ticker == "" {
+			items[i].Error = "'ticker' é obrigatório"
+			continue
+		}
+
+		r, err := s.parseDateRange("data_inicio", request.DataInicio, "data_fim", request.DataFim)
+		if err != nil {
+			items[i].Error = strings.TrimPrefix(err.Error(), "service: ")
+			continue
+		}
+		items[i].From, items[i].To = &r.From, &r.To
+
+		queries = append(queries, repository.AggregatedQuery{InstrumentCode: ticker, From: r.From, To: r.To})
+		positions = append(positions, i)
+	}
+
+	results, err := s.tradeRepo.GetAggregatedDataBatch(ctx, queries, session)
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao buscar dados agregados em lote: %w", err)
+	}
+	for i, result := range results {
+		item := &items[positions[i]]
+		item.Data, item.Found = result, result != nil
+	}
+
+	return items, nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// barAccumulator mantém o estado de uma barra em construção. As negociações chegam
+// na ordem de ScanTrades, então a primeira define a abertura e a última o fechamento.
+type barAccumulator struct {
+	start, end            time.Time
+	openTicks, closeTicks int64
+	highTicks, lowTicks   int64
+	volume                int64
+	financialTicks        int64 // Soma de preço (em ticks) * quantidade
+	count                 int64
+}
+
+// add acumula uma negociação na barra. Se o início ainda não foi definido (barras
+// por evento), ele passa a ser o horário da primeira negociação.
+func (b *barAccumulator) add(t *entity.Trade, ts time.Time) {
+	ticks := int64(math.Round(t.NegotiatedPrice * entity.PriceScale))
+	quantity := int64(t.NegotiatedQuantity)
+
+	if b.count == 0 {
+		b.openTicks, b.highTicks, b.lowTicks = ticks, ticks, ticks
+		if b.start.IsZero() {
+			b.start = ts
+		}
+	}
+	if ticks > b.highTicks {
+		b.highTicks = ticks
+	}
+	if ticks < b.lowTicks {
+		b.lowTicks = ticks
+	}
+	b.closeTicks = ticks
+	b.volume += quantity
+	b.financialTicks += ticks * quantity
+	b.count++
+	b.end = ts
+}
+
+// bar converte o acumulador na barra de saída.
+func (b *barAccumulator) bar() entity.Bar {
+	bar := entity.Bar{
+		Start:           b.start,
+		End:             b.end,
+		Open:            float64(b.openTicks) / entity.PriceScale,
+		High:            float64(b.highTicks) / entity.PriceScale,
+		Low:             float64(b.lowTicks) / entity.PriceScale,
+		Close:           float64(b.closeTicks) / entity.PriceScale,
+		Volume:          b.volume,
+		FinancialVolume: float64(b.financialTicks) / entity.PriceScale,
+		TradeCount:      b.count,
+	}
+	if b.volume > 0 {
+		// VWAP com 6 casas decimais, como em daily_summaries.vwap.
+		bar.VWAP = math.Round(float64(b.financialTicks)/float64(b.volume)*100) / (entity.PriceScale * 100)
+	}
+	return bar
+}
+
+// flatBar cria uma barra sem negociações no intervalo [start, end), com todos os
+// preços iguais ao fechamento anterior. Usada para preencher intervalos vazios.
+func flatBar(start, end time.Time, price float64) entity.Bar {
+	return entity.Bar{Start: start, End: end, Open: price, High: price, Low: price, Close: price, VWAP: price}
+}
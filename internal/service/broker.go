@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+)
+
+// BrokerService mantém o mapeamento opcional de códigos de participante para corretoras.
+type BrokerService interface {
+	ImportBrokers(ctx context.Context, r io.Reader) (int64, error)
+	ListBrokers(ctx context.Context) ([]entity.Broker, error)
+}
+
+type brokerServiceImpl struct {
+	brokerRepo repository.BrokerRepository
+}
+
+// NewBrokerService cria o serviço de corretoras.
+func NewBrokerService(repo repository.BrokerRepository) BrokerService {
+	return &brokerServiceImpl{brokerRepo: repo}
+}
+
+// ImportBrokers lê um arquivo "codigo;nome" (cabeçalho opcional, linhas iniciadas
+// com # ignoradas) e grava os nomes, substituindo os de códigos já cadastrados.
+// Retorna o número de corretoras gravadas.
+func (s *brokerServiceImpl) ImportBrokers(ctx context.Context, r io.Reader) (int64, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = ';'
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	byCode := make(map[int]int) // Posição em 'brokers' de cada código, para que a última linha prevaleça
+	var brokers []entity.Broker
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("service: falha ao ler arquivo de corretoras: %w", err)
+		}
+		if len(record) < 2 {
+			return 0, fmt.Errorf("service: linha %d inválida, esperado 'codigo;nome'", line)
+		}
+
+		code, err := strconv.Atoi(strings.TrimSpace(record[0]))
+		if err != nil {
+			if line == 1 {
+				continue // Cabeçalho
+			}
+			return 0, fmt.Errorf("service: linha %d: código de participante inválido '%s'", line, record[0])
+		}
+		name := strings.TrimSpace(record[1])
+		if code <= 0 || name == "" {
+			return 0, fmt.Errorf("service: linha %d: código deve ser positivo e nome não vazio", line)
+		}
+
+		if i, ok := byCode[code]; ok {
+			brokers[i].Name = name
+			continue
+		}
+		byCode[code] = len(brokers)
+		brokers = append(brokers, entity.Broker{ParticipantCode: code, Name: name})
+	}
+
+	count, err := s.brokerRepo.UpsertBrokers(ctx, brokers)
+	if err != nil {
+		return 0, fmt.Errorf("service: falha ao gravar corretoras: %w", err)
+	}
+	return count, nil
+}
+
+// ListBrokers retorna o mapeamento de corretoras cadastrado.
+func (s *brokerServiceImpl) ListBrokers(ctx context.Context) ([]entity.Broker, error) {
+	brokers, err := s.brokerRepo.ListBrokers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao listar corretoras: %w", err)
+	}
+	return brokers, nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+)
+
+const (
+	defaultBrokerLimit = 10
+	maxBrokerLimit     = 100
+)
+
+// RetrieveBrokerFlows calcula o fluxo por corretora do instrumento entre 'from' e 'to':
+// os 'limit' maiores compradores e vendedores por quantidade, a posição líquida de cada
+// participante e a concentração (HHI) dos lados comprador e vendedor. As datas seguem
+// a regra comum de parseDateRange; sessionStr restringe a um tipo de sessão.
+func (s *tradeServiceImpl) RetrieveBrokerFlows(ctx context.Context, instrumentCode, fromStr, toStr, limitStr, sessionStr string) (*entity.BrokerFlow, error) {
+	r, err := s.parseDateRange("from", fromStr, "to", toStr)
+	if err != nil {
+		return nil, err
+	}
+	session, err := parseSession(sessionStr)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := defaultBrokerLimit
+	if limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 || n > maxBrokerLimit {
+			return nil, invalidParameter("'limit' deve ser um inteiro entre 1 e %d", maxBrokerLimit)
+		}
+		limit = n
+	}
+
+	positions, err := s.tradeRepo.GetBrokerFlows(ctx, repository.TradeQuery{InstrumentCode: instrumentCode, From: r.From, To: r.To, Session: session})
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao calcular fluxo por corretora: %w", err)
+	}
+	if len(positions) == 0 {
+		return nil, fmt.Errorf("service: dados não encontrados para %s entre %s e %s",
+			instrumentCode, r.From.Format("2006-01-02"), r.To.Format("2006-01-02"))
+	}
+
+	flow := &entity.BrokerFlow{InstrumentCode: instrumentCode, From: r.From, To: r.To}
+	var buyVolume, sellVolume int64
+	for _, p := range positions {
+		buyVolume += p.BuyVolume
+		sellVolume += p.SellVolume
+		flow.FinancialVolume += p.BuyFinancialVolume
+	}
+	flow.Volume = buyVolume
+	flow.FinancialVolume = roundTo(flow.FinancialVolume, 4)
+
+	for i := range positions {
+		p := &positions[i]
+		p.NetVolume = p.BuyVolume - p.SellVolume
+		p.NetFinancialVolume = roundTo(p.BuyFinancialVolume-p.SellFinancialVolume, 4)
+		buyShare, sellShare := share(p.BuyVolume, buyVolume), share(p.SellVolume, sellVolume)
+		flow.BuyHHI += buyShare * buyShare
+		flow.SellHHI += sellShare * sellShare
+		p.BuyShare, p.SellShare = roundTo(buyShare, 4), roundTo(sellShare, 4)
+	}
+	flow.BuyHHI, flow.SellHHI = roundTo(flow.BuyHHI, 2), roundTo(flow.SellHHI, 2)
+
+	flow.TopBuyers = topBrokers(positions, limit, func(p entity.BrokerPosition) int64 { return p.BuyVolume })
+	flow.TopSellers = topBrokers(positions, limit, func(p entity.BrokerPosition) int64 { return p.SellVolume })
+
+	sort.SliceStable(positions, func(i, j int) bool {
+		if positions[i].NetVolume != positions[j].NetVolume {
+			return positions[i].NetVolume > positions[j].NetVolume
+		}
+		return positions[i].ParticipantCode < positions[j].ParticipantCode
+	})
+	flow.Brokers = positions
+
+	return flow, nil
+}
+
+// topBrokers retorna até 'limit' participantes com maior volume > 0 segundo 'volume',
+// desempatando pelo código do participante.
+func topBrokers(positions []entity.BrokerPosition, limit int, volume func(entity.BrokerPosition) int64) []entity.BrokerPosition {
+	top := make([]entity.BrokerPosition, 0, len(positions))
+	for _, p := range positions {
+		if volume(p) > 0 {
+			top = append(top, p)
+		}
+	}
+	sort.SliceStable(top, func(i, j int) bool {
+		if volume(top[i]) != volume(top[j]) {
+			return volume(top[i]) > volume(top[j])
+		}
+		return top[i].ParticipantCode < top[j].ParticipantCode
+	})
+	if len(top) > limit {
+		top = top[:limit]
+	}
+	return top
+}
+
+// share retorna part/total em percentual; total zero retorna 0.
+func share(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+// roundTo arredonda v para 'places' casas decimais.
+func roundTo(v float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(v*scale) / scale
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/calendar"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// defaultCalendarDays é o tamanho, em dias corridos, da listagem quando 'to' é omitido.
+const defaultCalendarDays = 30
+
+// CalendarService expõe o calendário de pregões da B3.
+type CalendarService interface {
+	ListTradingDays(ctx context.Context, fromStr, toStr string) (*entity.TradingCalendar, error)
+}
+
+type calendarServiceImpl struct {
+	calendar *calendar.Calendar
+	now      func() time.Time
+}
+
+// NewCalendarService cria o serviço de calendário. Sem calendário (nil), usa o embutido.
+func NewCalendarService(cal *calendar.Calendar) CalendarService {
+	if cal == nil {
+		cal = calendar.MustDefault()
+	}
+	return &calendarServiceImpl{calendar: cal, now: time.Now}
+}
+
+// ListTradingDays lista pregões e feriados entre 'from' e 'to' (inclusivos, YYYY-MM-DD).
+// Diferente das consultas de negociações, datas futuras são aceitas. Sem 'from', usa o
+// dia atual; sem 'to', os defaultCalendarDays dias seguintes a 'from'. Intervalos com
+// anos ausentes do arquivo de feriados são recusados, pois os feriados seriam omitidos.
+func (s *calendarServiceImpl) ListTradingDays(ctx context.Context, fromStr, toStr string) (*entity.TradingCalendar, error) {
+	now := s.now().In(entity.B3Location)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var err error
+	if fromStr != "" {
+		if from, err = parseDate("from", fromStr); err != nil {
+			return nil, err
+		}
+	}
+	to := from.AddDate(0, 0, defaultCalendarDays)
+	if toStr != "" {
+		if to, err = parseDate("to", toStr); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case from.After(to):
+		return nil, invalidParameter("'from' (%s) é posterior a 'to' (%s)", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	case to.Sub(from) >= MaxQueryRangeDays*24*time.Hour:
+		return nil, invalidParameter("intervalo entre 'from' e 'to' excede %d dias", MaxQueryRangeDays)
+	}
+	if uncovered := s.calendar.Uncovered(from, to); len(uncovered) > 0 {
+		return nil, invalidParameter("calendário de feriados não cobre %s (anos cobertos: %s)",
+			joinYears(uncovered), joinYears(s.calendar.Years()))
+	}
+
+	result := s.calendar.Range(from, to)
+	return &result, nil
+}
+
+// joinYears formata uma lista de anos separada por vírgulas.
+func joinYears(years []int) string {
+	parts := make([]string, len(years))
+	for i, year := range years {
+		parts[i] = strconv.Itoa(year)
+	}
+	return strings.Join(parts, ", ")
+}
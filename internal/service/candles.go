@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+)
+
+// Horários do pregão regular do mercado à vista da B3, no formato de closing_time.
+// O fim é exclusivo: negociações a partir das 16:55 pertencem ao call de fechamento e ao after-market.
+const (
+	RegularSessionStart = "100000000"
+	RegularSessionEnd   = "165500000"
+)
+
+// candleIntervals são as durações aceitas para as barras de tempo.
+var candleIntervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+}
+
+// RetrieveCandles constrói barras de tempo OHLCV a partir de closing_time para um pregão
+// (dateStr, YYYY-MM-DD). Com fill, intervalos sem negociações entre a primeira e a última
+// barra são preenchidos com barras sem volume no preço do fechamento anterior. Com
+// regularOnly, apenas negociações no horário do pregão regular (RegularSessionStart a
+// RegularSessionEnd) são consideradas; sessionStr filtra pelo tipo de sessão informado
+// pela B3.
+func (s *tradeServiceImpl) RetrieveCandles(ctx context.Context, instrumentCode, interval, dateStr, sessionStr string, fill, regularOnly bool) (*entity.BarSeries, error) {
+	duration, ok := candleIntervals[interval]
+	if !ok {
+		return nil, invalidParameter("'interval' inválido ('%s'). Use 1m, 5m, 15m ou 1h", interval)
+	}
+	tradeDate, err := s.parseTradeDate("date", dateStr)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := parseSession(sessionStr)
+	if err != nil {
+		return nil, err
+	}
+
+	q := repository.TradeQuery{InstrumentCode: instrumentCode, From: tradeDate, To: tradeDate, Session: session}
+	if regularOnly {
+		q.FromTime, q.ToTime = RegularSessionStart, RegularSessionEnd
+	}
+
+	series := &entity.BarSeries{InstrumentCode: instrumentCode, TradeDate: tradeDate, Type: "time", Interval: interval, Bars: []entity.Bar{}}
+	var current *barAccumulator
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		series.Bars = append(series.Bars, current.bar())
+	}
+
+	err = s.tradeRepo.ScanTrades(ctx, q, func(t *entity.Trade) error {
+		ts, err := t.Timestamp()
+		if err != nil {
+			return fmt.Errorf("service: %w", err)
+		}
+		start := ts.Truncate(duration)
+		if current != nil && start.Equal(current.start) {
+			current.add(t, ts)
+			return nil
+		}
+
+		flush()
+		if fill && current != nil {
+			lastClose := series.Bars[len(series.Bars)-1].Close
+			for gap := current.start.Add(duration); gap.Before(start); gap = gap.Add(duration) {
+				series.Bars = append(series.Bars, flatBar(gap, gap.Add(duration), lastClose))
+			}
+		}
+		current = &barAccumulator{start: start}
+		current.add(t, ts)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao construir barras: %w", err)
+	}
+	flush()
+
+	if len(series.Bars) == 0 {
+		return nil, fmt.Errorf("service: dados não encontrados para %s em %s", instrumentCode, dateStr)
+	}
+	// Em barras de tempo, End é o limite do intervalo, não o horário da última negociação.
+	for i := range series.Bars {
+		series.Bars[i].End = series.Bars[i].Start.Add(duration)
+	}
+	return series, nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+)
+
+// Tipos de barras por evento.
+const (
+	BarTypeTick   = "tick"   // Fecha a cada N negociações
+	BarTypeVolume = "volume" // Fecha a cada N ações negociadas
+	BarTypeDollar = "dollar" // Fecha a cada R$ N negociados
+)
+
+// RetrieveEventBars constrói barras por evento para um pregão (dateStr, YYYY-MM-DD)
+// seguindo a ordem das negociações: cada barra fecha na negociação que faz o
+// acumulado (negócios, quantidade ou volume financeiro, conforme barType) atingir
+// o limite. A negociação que cruza o limite pertence inteira à barra que ela fecha;
+// a última barra do dia pode ficar abaixo do limite. sessionStr restringe as
+// negociações a um tipo de sessão.
+func (s *tradeServiceImpl) RetrieveEventBars(ctx context.Context, instrumentCode, barType, thresholdStr, dateStr, sessionStr string) (*entity.BarSeries, error) {
+	threshold, err := strconv.ParseFloat(thresholdStr, 64)
+	if err != nil || threshold <= 0 {
+		return nil, invalidParameter("'threshold' deve ser um número positivo")
+	}
+
+	// limit e progress são comparados na unidade de cada tipo: negociações, ações
+	// ou ticks de preço * quantidade (volume financeiro sem arredondamento).
+	var limit int64
+	var progress func(b *barAccumulator) int64
+	switch barType {
+	case BarTypeTick:
+		limit, progress = int64(math.Ceil(threshold)), func(b *barAccumulator) int64 { return b.count }
+	case BarTypeVolume:
+		limit, progress = int64(math.Ceil(threshold)), func(b *barAccumulator) int64 { return b.volume }
+	case BarTypeDollar:
+		limit, progress = int64(math.Round(threshold*entity.PriceScale)), func(b *barAccumulator) int64 { return b.financialTicks }
+	default:
+		return nil, invalidParameter("'type' inválido ('%s'). Use tick, volume ou dollar", barType)
+	}
+
+	tradeDate, err := s.parseTradeDate("date", dateStr)
+	if err != nil {
+		return nil, err
+	}
+	session, err := parseSession(sessionStr)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &entity.BarSeries{InstrumentCode: instrumentCode, TradeDate: tradeDate, Type: barType, Threshold: threshold, Bars: []entity.Bar{}}
+	current := &barAccumulator{}
+
+	q := repository.TradeQuery{InstrumentCode: instrumentCode, From: tradeDate, To: tradeDate, Session: session}
+	err = s.tradeRepo.ScanTrades(ctx, q, func(t *entity.Trade) error {
+		ts, err := t.Timestamp()
+		if err != nil {
+			return fmt.Errorf("service: %w", err)
+		}
+		current.add(t, ts)
+		if progress(current) >= limit {
+			series.Bars = append(series.Bars, current.bar())
+			current = &barAccumulator{}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao construir barras: %w", err)
+	}
+	if current.count > 0 {
+		series.Bars = append(series.Bars, current.bar())
+	}
+
+	if len(series.Bars) == 0 {
+		return nil, fmt.Errorf("service: dados não encontrados para %s em %s", instrumentCode, dateStr)
+	}
+	return series, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+)
+
+// fakeTradeRepository responde com dados fixos às leituras usadas nos testes do serviço.
+// Os demais métodos da interface não são usados e entram em pânico se chamados.
+type fakeTradeRepository struct {
+	repository.TradeRepository
+	days      []entity.DailyOHLCV
+	variances []entity.IntradayVariance
+	idChecks  []entity.TradeIDCheck
+	totals    map[string]map[string]entity.LoadTotals
+
+	// Filtro da última chamada a ListOutliers.
+	outlierQuery repository.OutlierQuery
+
+	// Argumentos da última chamada a RecordLoadVerification.
+	recordedDiscrepancies []int32
+	recordedRejected      int64
+}
+
+func (f *fakeTradeRepository) GetDailySeries(ctx context.Context, instrumentCode string, from, to time.Time, session *int) ([]entity.DailyOHLCV, error) {
+	return f.days, nil
+}
+
+func (f *fakeTradeRepository) GetIntradayVariance(ctx context.Context, q repository.TradeQuery, minutes int) ([]entity.IntradayVariance, error) {
+	return f.variances, nil
+}
+
+func (f *fakeTradeRepository) CheckTradeIDs(ctx context.Context, table string, tradeDates []time.Time, step int64) ([]entity.TradeIDCheck, error) {
+	return f.idChecks, nil
+}
+
+func (f *fakeTradeRepository) GetLoadTotals(ctx context.Context, tradeDates []time.Time) (map[string]map[string]entity.LoadTotals, error) {
+	return f.totals, nil
+}
+
+func (f *fakeTradeRepository) RecordLoadVerification(ctx context.Context, fileName string, tradeDates []time.Time, tradeCounts []int64, discrepancies []int32, rejected int64) error {
+	f.recordedDiscrepancies, f.recordedRejected = discrepancies, rejected
+	return nil
+}
+
+func (f *fakeTradeRepository) ListOutliers(ctx context.Context, q repository.OutlierQuery) ([]entity.TradeOutlier, error) {
+	f.outlierQuery = q
+	return []entity.TradeOutlier{}, nil
+}
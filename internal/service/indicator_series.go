@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+)
+
+const (
+	// dailyInterval identifica as barras diárias (de daily_summaries) nos indicadores.
+	dailyInterval = "1d"
+
+	maxIndicatorPeriod = 200
+	defaultStdDev      = 2
+	// maxIntradayWarmupDays limita os pregões lidos antes de 'from' para aquecer
+	// indicadores em barras intradiárias.
+	maxIntradayWarmupDays = 30
+	// regularSessionMinutes é a duração do pregão regular (RegularSessionStart a
+	// RegularSessionEnd), usada para estimar as barras intradiárias por pregão.
+	regularSessionMinutes = 415
+)
+
+// indicatorDefaults são os períodos padrão de cada indicador aceito.
+var indicatorDefaults = map[string][]int{
+	"sma":       {20},
+	"ema":       {20},
+	"rsi":       {14},
+	"macd":      {12, 26, 9},
+	"bollinger": {20},
+	"atr":       {14},
+}
+
+// IndicatorRequest reúne os parâmetros de RetrieveIndicators como recebidos na API.
+// 'Date' consulta um único pregão; 'From'/'To' um intervalo. Sem nenhum deles, usa o
+// pregão anterior a hoje.
+type IndicatorRequest struct {
+	Name     string // sma, ema, rsi, macd, bollinger ou atr
+	Period   string // Período em barras; em macd, "rápida,lenta,sinal"
+	StdDev   string // Multiplicador do desvio padrão em bollinger (padrão 2)
+	Interval string // 1d (padrão) ou 1m, 5m, 15m, 1h
+	Date     string
+	From, To string
+	Session  string // Tipo de sessão; vazio considera todas
+}
+
+// RetrieveIndicators calcula um indicador técnico sobre as barras diárias ou
+// intradiárias do instrumento no pregão ou intervalo pedido. Para que os primeiros
+// valores do intervalo não dependam de onde a leitura começa, as barras de pregões
+// anteriores a 'from' necessárias ao aquecimento do indicador (warmupBars) também são
+// lidas, mas não retornadas. Em barras intradiárias, o aquecimento é limitado a
+// maxIntradayWarmupDays pregões.
+func (s *tradeServiceImpl) RetrieveIndicators(ctx context.Context, instrumentCode string, req IndicatorRequest) (*entity.IndicatorSeries, error) {
+	name := strings.ToLower(req.Name)
+	defaults, ok := indicatorDefaults[name]
+	if !ok {
+		return nil, invalidParameter("'name' inválido ('%s'). Use sma, ema, rsi, macd, bollinger ou atr", req.Name)
+	}
+	periods, err := parsePeriods(req.Period, defaults)
+	if err != nil {
+		return nil, err
+	}
+	if name == "macd" && periods[0] >= periods[1] {
+		return nil, invalidParameter("em macd, o período rápido deve ser menor que o lento")
+	}
+
+	var stdDev float64
+	if name == "bollinger" {
+		stdDev = defaultStdDev
+		if req.StdDev != "" {
+			if stdDev, err = strconv.ParseFloat(req.StdDev, 64); err != nil || stdDev <= 0 || stdDev > 10 {
+				return nil, invalidParameter("'std_dev' deve ser um número maior que 0 e até 10")
+			}
+		}
+	}
+
+	interval := req.Interval
+	if interval == "" {
+		interval = dailyInterval
+	}
+	duration, intraday := candleIntervals[interval]
+	if !intraday && interval != dailyInterval {
+		return nil, invalidParameter("'interval' inválido ('%s'). Use 1d, 1m, 5m, 15m ou 1h", interval)
+	}
+
+	session, err := parseSession(req.Session)
+	if err != nil {
+		return nil, err
+	}
+	r, err := s.parseDayOrRange(req.Date, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pregões de aquecimento: um por barra diária ou, em barras intradiárias, os
+	// necessários para cobrir as barras do pregão regular, mais um de margem.
+	warmupBars := indicatorWarmup(name, periods)
+	warmupDays := warmupBars
+	if intraday {
+		perDay := int(math.Ceil(regularSessionMinutes / duration.Minutes()))
+		warmupDays = min((warmupBars+perDay-1)/perDay+1, maxIntradayWarmupDays)
+	}
+	start := r.From
+	if warmupDays > 0 {
+		start, _ = s.calendar.LastTradingDays(s.calendar.PreviousTradingDay(r.From), warmupDays)
+		s.warnUncovered(start, r.From)
+	}
+
+	var bars []entity.Bar
+	if intraday {
+		bars, err = s.scanTimeBars(ctx, repository.TradeQuery{InstrumentCode: instrumentCode, From: start, To: r.To, Session: session}, duration)
+	} else {
+		bars, err = s.dailyBars(ctx, instrumentCode, start, r.To, session)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Barras intradiárias começam no fuso da B3; a comparação usa a data do pregão.
+	first := len(bars)
+	for i, b := range bars {
+		if tradeDate := time.Date(b.Start.Year(), b.Start.Month(), b.Start.Day(), 0, 0, 0, 0, time.UTC); !tradeDate.Before(r.From) {
+			first = i
+			break
+		}
+	}
+	if first == len(bars) {
+		return nil, fmt.Errorf("service: dados não encontrados para %s entre %s e %s",
+			instrumentCode, r.From.Format("2006-01-02"), r.To.Format("2006-01-02"))
+	}
+
+	outputs := computeIndicator(name, periods, stdDev, bars)
+	series := &entity.IndicatorSeries{
+		InstrumentCode: instrumentCode, Name: name, Periods: periods, StdDev: stdDev,
+		Interval: interval, From: r.From, To: r.To, WarmupBars: first,
+		Points: make([]entity.IndicatorPoint, 0, len(bars)-first),
+	}
+	for i := first; i < len(bars); i++ {
+		point := entity.IndicatorPoint{Time: bars[i].Start, Close: bars[i].Close}
+		for key, values := range outputs {
+			if math.IsNaN(values[i]) {
+				continue
+			}
+			if point.Values == nil {
+				point.Values = make(map[string]float64, len(outputs))
+			}
+			point.Values[key] = roundTo(values[i], 6)
+		}
+		series.Points = append(series.Points, point)
+	}
+	return series, nil
+}
+
+// parsePeriods interpreta a lista de períodos separados por vírgula, que deve ter a
+// mesma quantidade de valores do padrão do indicador. Vazio usa o padrão.
+func parsePeriods(value string, defaults []int) ([]int, error) {
+	if value == "" {
+		return defaults, nil
+	}
+	parts := strings.Split(value, ",")
+	if len(parts) != len(defaults) {
+		return nil, invalidParameter("'period' deve ter %d valor(es) separados por vírgula", len(defaults))
+	}
+	periods := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 1 || n > maxIndicatorPeriod {
+			return nil, invalidParameter("'period' deve conter inteiros entre 1 e %d", maxIndicatorPeriod)
+		}
+		periods[i] = n
+	}
+	return periods, nil
+}
+
+// indicatorWarmup retorna quantas barras anteriores ao intervalo o indicador precisa
+// para que seus valores no intervalo não dependam do início da leitura.
+func indicatorWarmup(name string, periods []int) int {
+	switch name {
+	case "ema":
+		return emaWarmupFactor * periods[0]
+	case "macd":
+		return emaWarmupFactor * (periods[1] + periods[2])
+	case "rsi", "atr":
+		return wilderWarmupFactor * periods[0]
+	default: // sma, bollinger
+		return periods[0] - 1
+	}
+}
+
+// computeIndicator calcula as saídas do indicador, por nome, alinhadas às barras.
+func computeIndicator(name string, periods []int, stdDev float64, bars []entity.Bar) map[string][]float64 {
+	closes := make([]float64, len(bars))
+	for i, b := range bars {
+		closes[i] = b.Close
+	}
+
+	switch name {
+	case "sma":
+		return map[string][]float64{"sma": smaSeries(closes, periods[0])}
+	case "ema":
+		return map[string][]float64{"ema": emaSeries(closes, periods[0])}
+	case "rsi":
+		return map[string][]float64{"rsi": rsiSeries(closes, periods[0])}
+	case "macd":
+		macd, signal, histogram := macdSeries(closes, periods[0], periods[1], periods[2])
+		return map[string][]float64{"macd": macd, "signal": signal, "histogram": histogram}
+	case "bollinger":
+		middle, upper, lower := bollingerSeries(closes, periods[0], stdDev)
+		return map[string][]float64{"middle": middle, "upper": upper, "lower": lower}
+	default: // atr
+		highs, lows := make([]float64, len(bars)), make([]float64, len(bars))
+		for i, b := range bars {
+			highs[i], lows[i] = b.High, b.Low
+		}
+		return map[string][]float64{"atr": atrSeries(highs, lows, closes, periods[0])}
+	}
+}
+
+// dailyBars lê a série diária do instrumento como barras, com Start na data do pregão.
+func (s *tradeServiceImpl) dailyBars(ctx context.Context, instrumentCode string, from, to time.Time, session *int) ([]entity.Bar, error) {
+	days, err := s.tradeRepo.GetDailySeries(ctx, instrumentCode, from, to, session)
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao buscar série diária: %w", err)
+	}
+	bars := make([]entity.Bar, len(days))
+	for i, d := range days {
+		bars[i] = entity.Bar{
+			Start: d.TradeDate, End: d.TradeDate.AddDate(0, 0, 1),
+			Open: d.Open, High: d.High, Low: d.Low, Close: d.Close,
+			Volume: d.Volume, FinancialVolume: d.FinancialVolume, TradeCount: d.TradeCount,
+		}
+	}
+	return bars, nil
+}
+
+// scanTimeBars constrói as barras de tempo de 'duration' das negociações que atendem ao
+// filtro, em todos os pregões do intervalo, sem preencher intervalos vazios.
+func (s *tradeServiceImpl) scanTimeBars(ctx context.Context, q repository.TradeQuery, duration time.Duration) ([]entity.Bar, error) {
+	var bars []entity.Bar
+	var current *barAccumulator
+	err := s.tradeRepo.ScanTrades(ctx, q, func(t *entity.Trade) error {
+		ts, err := t.Timestamp()
+		if err != nil {
+			return fmt.Errorf("service: %w", err)
+		}
+		start := ts.Truncate(duration)
+		if current == nil || !start.Equal(current.start) {
+			if current != nil {
+				bars = append(bars, current.bar())
+			}
+			current = &barAccumulator{start: start}
+		}
+		current.add(t, ts)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao construir barras: %w", err)
+	}
+	if current != nil {
+		bars = append(bars, current.bar())
+	}
+	for i := range bars {
+		bars[i].End = bars[i].Start.Add(duration)
+	}
+	return bars, nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import "math"
+
+// Funções do motor de indicadores técnicos. Cada uma recebe a série de barras em ordem
+// cronológica e retorna uma série alinhada a ela, com NaN nas barras em que o
+// indicador ainda não tem histórico suficiente.
+
+// Fatores de aquecimento, em múltiplos do período: com eles, o peso das barras
+// anteriores ao aquecimento na EMA (alfa = 2 / (period + 1)) e na suavização de Wilder
+// (alfa = 1 / period) cai para cerca de e^-10.
+const (
+	emaWarmupFactor    = 5
+	wilderWarmupFactor = 10
+)
+
+// nanSeries cria uma série de n valores NaN.
+func nanSeries(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
+
+// smaSeries é a média móvel simples de 'period' barras.
+func smaSeries(values []float64, period int) []float64 {
+	out := nanSeries(len(values))
+	var sum float64
+	for i, v := range values {
+		sum += v
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i+1 >= period {
+			out[i] = sum / float64(period)
+		}
+	}
+	return out
+}
+
+// emaSeries é a média móvel exponencial de 'period' barras (alfa = 2 / (period + 1)),
+// iniciada pela média simples dos primeiros 'period' valores definidos. Valores NaN no
+// início da entrada (ex: a linha do MACD) são ignorados.
+func emaSeries(values []float64, period int) []float64 {
+	out := nanSeries(len(values))
+	alpha := 2 / float64(period+1)
+	var sum float64
+	count := 0
+	for i, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		count++
+		switch {
+		case count < period:
+			sum += v
+		case count == period:
+			out[i] = (sum + v) / float64(period)
+		default:
+			out[i] = out[i-1] + alpha*(v-out[i-1])
+		}
+	}
+	return out
+}
+
+// wilderSeries aplica a suavização de Wilder (alfa = 1 / period) a partir do índice
+// 'start', iniciada pela média simples dos 'period' primeiros valores.
+func wilderSeries(values []float64, period, start int) []float64 {
+	out := nanSeries(len(values))
+	var sum float64
+	for i := start; i < len(values); i++ {
+		n := i - start + 1
+		switch {
+		case n < period:
+			sum += values[i]
+		case n == period:
+			out[i] = (sum + values[i]) / float64(period)
+		default:
+			out[i] = (out[i-1]*float64(period-1) + values[i]) / float64(period)
+		}
+	}
+	return out
+}
+
+// rsiSeries é o índice de força relativa de Wilder (0 a 100) de 'period' barras.
+func rsiSeries(closes []float64, period int) []float64 {
+	gains := make([]float64, len(closes))
+	losses := make([]float64, len(closes))
+	for i := 1; i < len(closes); i++ {
+		change := closes[i] - closes[i-1]
+		gains[i], losses[i] = math.Max(change, 0), math.Max(-change, 0)
+	}
+	avgGain, avgLoss := wilderSeries(gains, period, 1), wilderSeries(losses, period, 1)
+
+	out := nanSeries(len(closes))
+	for i := range closes {
+		switch {
+		case math.IsNaN(avgGain[i]):
+		case avgLoss[i] == 0:
+			out[i] = 100
+		default:
+			out[i] = 100 - 100/(1+avgGain[i]/avgLoss[i])
+		}
+	}
+	return out
+}
+
+// macdSeries retorna a linha do MACD (EMA rápida - EMA lenta), a linha de sinal (EMA
+// da linha do MACD) e o histograma (MACD - sinal).
+func macdSeries(closes []float64, fast, slow, signal int) (macd, signalLine, histogram []float64) {
+	fastEMA, slowEMA := emaSeries(closes, fast), emaSeries(closes, slow)
+	macd = make([]float64, len(closes))
+	for i := range closes {
+		macd[i] = fastEMA[i] - slowEMA[i]
+	}
+	signalLine = emaSeries(macd, signal)
+	histogram = make([]float64, len(closes))
+	for i := range closes {
+		histogram[i] = macd[i] - signalLine[i]
+	}
+	return macd, signalLine, histogram
+}
+
+// bollingerSeries retorna as Bandas de Bollinger: a média simples de 'period' barras e
+// as bandas a 'k' desvios padrão (populacionais) acima e abaixo dela.
+func bollingerSeries(closes []float64, period int, k float64) (middle, upper, lower []float64) {
+	middle = smaSeries(closes, period)
+	upper, lower = nanSeries(len(closes)), nanSeries(len(closes))
+	for i := period - 1; i < len(closes); i++ {
+		var sum float64
+		for _, v := range closes[i+1-period : i+1] {
+			sum += (v - middle[i]) * (v - middle[i])
+		}
+		std := math.Sqrt(sum / float64(period))
+		upper[i], lower[i] = middle[i]+k*std, middle[i]-k*std
+	}
+	return middle, upper, lower
+}
+
+// atrSeries é o Average True Range de Wilder de 'period' barras. O true range da
+// primeira barra é a sua amplitude.
+func atrSeries(highs, lows, closes []float64, period int) []float64 {
+	tr := make([]float64, len(closes))
+	for i := range closes {
+		tr[i] = highs[i] - lows[i]
+		if i > 0 {
+			tr[i] = math.Max(tr[i], math.Max(math.Abs(highs[i]-closes[i-1]), math.Abs(lows[i]-closes[i-1])))
+		}
+	}
+	return wilderSeries(tr, period, 0)
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"math"
+	"testing"
+)
+
+// assertSeries compara uma série com a esperada; NaN na esperada exige NaN na obtida.
+func assertSeries(t *testing.T, name string, got, want []float64, tolerance float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: %d valores, esperado %d", name, len(got), len(want))
+	}
+	for i := range want {
+		switch {
+		case math.IsNaN(want[i]):
+			if !math.IsNaN(got[i]) {
+				t.Errorf("%s[%d] = %.6f, esperado NaN", name, i, got[i])
+			}
+		case math.IsNaN(got[i]) || math.Abs(got[i]-want[i]) > tolerance:
+			t.Errorf("%s[%d] = %.6f, esperado %.6f", name, i, got[i], want[i])
+		}
+	}
+}
+
+// firstDefined retorna o índice do primeiro valor que não é NaN, ou -1.
+func firstDefined(values []float64) int {
+	for i, v := range values {
+		if !math.IsNaN(v) {
+			return i
+		}
+	}
+	return -1
+}
+
+var nan = math.NaN()
+
+// rsiCloses é a série de fechamentos do exemplo clássico do RSI de 14 períodos de
+// Wilder (StockCharts), cujo primeiro valor é 70,53.
+var rsiCloses = []float64{
+	44.3389, 44.0902, 44.1497, 43.6124, 44.2778, 44.8264, 45.0955, 45.4245, 45.8433, 46.0826,
+	45.8931, 46.0328, 45.6140, 46.2820, 46.2820, 46.0028, 46.0328, 46.4116, 46.2222, 45.6439,
+	46.2122, 46.2521, 45.7137, 46.4515, 45.7835, 45.3548, 44.0288, 44.1783, 44.2181, 44.5672,
+	43.4205, 42.6628, 43.1314,
+}
+
+func TestRSISeries(t *testing.T) {
+	tests := []struct {
+		name   string
+		closes []float64
+		period int
+		want   []float64
+	}{
+		{
+			name:   "exemplo de Wilder",
+			closes: rsiCloses,
+			period: 14,
+			want: append(nanSeries(14),
+				70.53, 66.32, 66.55, 69.41, 66.36, 57.97, 62.93, 63.26, 56.06, 62.38,
+				54.71, 50.42, 39.99, 41.46, 41.87, 45.46, 37.30, 33.08, 37.77),
+		},
+		{
+			// Variações +1, -1, +2: ganho médio 1, perda média 1/3 → 100 - 100/4 = 75;
+			// depois +1: ganho (1*2 + 1)/3 = 1, perda (1/3*2)/3 = 2/9 → 100 - 100/5,5.
+			name:   "período 3",
+			closes: []float64{10, 11, 10, 12, 13},
+			period: 3,
+			want:   []float64{nan, nan, nan, 75, 100 - 100/5.5},
+		},
+		{
+			name:   "somente altas",
+			closes: []float64{1, 2, 3, 4},
+			period: 2,
+			want:   []float64{nan, nan, 100, 100},
+		},
+		{
+			name:   "somente quedas",
+			closes: []float64{4, 3, 2, 1},
+			period: 2,
+			want:   []float64{nan, nan, 0, 0},
+		},
+		{
+			// O RSI precisa de 'period' variações, isto é, period + 1 fechamentos.
+			name:   "série curta",
+			closes: []float64{1, 2, 3},
+			period: 3,
+			want:   []float64{nan, nan, nan},
+		},
+		{
+			name:   "série vazia",
+			closes: []float64{},
+			period: 14,
+			want:   []float64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSeries(t, "rsi", rsiSeries(tt.closes, tt.period), tt.want, 0.005)
+		})
+	}
+}
+
+func TestMACDSeries(t *testing.T) {
+	tests := []struct {
+		name                  string
+		closes                []float64
+		fast, slow, signal    int
+		macd, line, histogram []float64
+	}{
+		{
+			// EMA(2): semente (2+4)/2 = 3, depois alfa 2/3; EMA(3): semente 14/3, alfa 1/2.
+			// O sinal (EMA(2) do MACD) começa pela média das duas primeiras linhas do MACD.
+			name:      "períodos 2, 3 e 2",
+			closes:    []float64{2, 4, 8, 4, 2, 4},
+			fast:      2,
+			slow:      3,
+			signal:    2,
+			macd:      []float64{nan, nan, 1.666667, 0.444444, -0.240741, 0.058642},
+			line:      []float64{nan, nan, nan, 1.055556, 0.191358, 0.102881},
+			histogram: []float64{nan, nan, nan, -0.611111, -0.432099, -0.044239},
+		},
+		{
+			// Tendência linear: as EMAs ficam atrás do preço em (period - 1) / 2 barras,
+			// então o MACD é constante (3 - 2) / 2 = 0,5 e o histograma é zero.
+			name:      "tendência linear",
+			closes:    []float64{1, 2, 3, 4, 5, 6},
+			fast:      2,
+			slow:      3,
+			signal:    2,
+			macd:      []float64{nan, nan, 0.5, 0.5, 0.5, 0.5},
+			line:      []float64{nan, nan, nan, 0.5, 0.5, 0.5},
+			histogram: []float64{nan, nan, nan, 0, 0, 0},
+		},
+		{
+			// A linha do MACD só existe com 'slow' barras e o sinal com slow + signal - 1.
+			name:      "série curta",
+			closes:    []float64{1, 2, 3, 4},
+			fast:      2,
+			slow:      3,
+			signal:    3,
+			macd:      []float64{nan, nan, 0.5, 0.5},
+			line:      []float64{nan, nan, nan, nan},
+			histogram: []float64{nan, nan, nan, nan},
+		},
+		{
+			name:      "menos barras que a EMA lenta",
+			closes:    []float64{1, 2},
+			fast:      12,
+			slow:      26,
+			signal:    9,
+			macd:      []float64{nan, nan},
+			line:      []float64{nan, nan},
+			histogram: []float64{nan, nan},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			macd, line, histogram := macdSeries(tt.closes, tt.fast, tt.slow, tt.signal)
+			assertSeries(t, "macd", macd, tt.macd, 1e-6)
+			assertSeries(t, "signal", line, tt.line, 1e-6)
+			assertSeries(t, "histogram", histogram, tt.histogram, 1e-6)
+		})
+	}
+}
+
+func TestBollingerSeries(t *testing.T) {
+	tests := []struct {
+		name                 string
+		closes               []float64
+		period               int
+		k                    float64
+		middle, upper, lower []float64
+	}{
+		{
+			// Janelas (1,2,3), (2,3,4), (3,4,5): desvio populacional sqrt(2/3) = 0,816497.
+			name:   "período 3, 2 desvios",
+			closes: []float64{1, 2, 3, 4, 5},
+			period: 3,
+			k:      2,
+			middle: []float64{nan, nan, 2, 3, 4},
+			upper:  []float64{nan, nan, 3.632993, 4.632993, 5.632993},
+			lower:  []float64{nan, nan, 0.367007, 1.367007, 2.367007},
+		},
+		{
+			// Janela (2, 4, 4, 4, 5, 5, 7, 9): média 5 e desvio populacional 2.
+			name:   "desvio exato",
+			closes: []float64{2, 4, 4, 4, 5, 5, 7, 9},
+			period: 8,
+			k:      1.5,
+			middle: append(nanSeries(7), 5),
+			upper:  append(nanSeries(7), 8),
+			lower:  append(nanSeries(7), 2),
+		},
+		{
+			name:   "preço constante",
+			closes: []float64{10, 10, 10},
+			period: 2,
+			k:      2,
+			middle: []float64{nan, 10, 10},
+			upper:  []float64{nan, 10, 10},
+			lower:  []float64{nan, 10, 10},
+		},
+		{
+			name:   "série curta",
+			closes: []float64{1, 2},
+			period: 20,
+			k:      2,
+			middle: []float64{nan, nan},
+			upper:  []float64{nan, nan},
+			lower:  []float64{nan, nan},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			middle, upper, lower := bollingerSeries(tt.closes, tt.period, tt.k)
+			assertSeries(t, "middle", middle, tt.middle, 1e-6)
+			assertSeries(t, "upper", upper, tt.upper, 1e-6)
+			assertSeries(t, "lower", lower, tt.lower, 1e-6)
+		})
+	}
+}
+
+func TestIndicatorWarmup(t *testing.T) {
+	tests := []struct {
+		name    string
+		periods []int
+		want    int
+	}{
+		{"sma", []int{20}, 19},
+		{"bollinger", []int{20}, 19},
+		{"ema", []int{10}, 50},
+		{"macd", []int{12, 26, 9}, 175},
+		{"rsi", []int{14}, 140},
+		{"atr", []int{14}, 140},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := indicatorWarmup(tt.name, tt.periods); got != tt.want {
+				t.Errorf("indicatorWarmup(%s, %v) = %d, esperado %d", tt.name, tt.periods, got, tt.want)
+			}
+		})
+	}
+}
+
+// As séries começam a ter valores exatamente na barra em que o indicador tem histórico
+// suficiente; o aquecimento de indicatorWarmup é sempre ao menos esse número de barras.
+func TestIndicatorFirstValue(t *testing.T) {
+	closes := make([]float64, 300)
+	highs := make([]float64, len(closes))
+	lows := make([]float64, len(closes))
+	for i := range closes {
+		closes[i] = 100 + 10*math.Sin(float64(i)/7)
+		highs[i], lows[i] = closes[i]+1, closes[i]-1
+	}
+	_, macdSignal, _ := macdSeries(closes, 12, 26, 9)
+	_, bollingerUpper, _ := bollingerSeries(closes, 20, 2)
+
+	tests := []struct {
+		name    string
+		periods []int
+		series  []float64
+		want    int
+	}{
+		{"sma", []int{20}, smaSeries(closes, 20), 19},
+		{"ema", []int{10}, emaSeries(closes, 10), 9},
+		{"rsi", []int{14}, rsiSeries(closes, 14), 14},
+		{"macd", []int{12, 26, 9}, macdSignal, 33},
+		{"bollinger", []int{20}, bollingerUpper, 19},
+		{"atr", []int{14}, atrSeries(highs, lows, closes, 14), 13},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := firstDefined(tt.series); got != tt.want {
+				t.Errorf("primeiro valor de %s na barra %d, esperado %d", tt.name, got, tt.want)
+			}
+			if warmup := indicatorWarmup(tt.name, tt.periods); warmup < tt.want {
+				t.Errorf("aquecimento de %s = %d barras, menor que as %d necessárias", tt.name, warmup, tt.want)
+			}
+		})
+	}
+}
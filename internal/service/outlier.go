@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultOutlierLimit = 100
+	maxOutlierLimit     = 1000
+)
+
+// DefaultOutlierRules são os critérios usados pela detecção ao final da ingestão e
+// os padrões de "ingest outliers detect": negócios de 20x o tamanho médio dos últimos
+// 30 dias corridos e preços a 2% ou 6 desvios padrão da média dos 50 negócios anteriores.
+var DefaultOutlierRules = repository.OutlierRules{
+	BlockMultiple:  20,
+	HistoryDays:    30,
+	PriceWindow:    50,
+	DeviationPct:   2,
+	DeviationSigma: 6,
+}
+
+// OutlierRequest reúne os parâmetros de RetrieveOutliers como recebidos na API.
+// 'Date' consulta um único pregão; 'From'/'To' um intervalo. Sem nenhum deles, usa o
+// pregão anterior a hoje.
+type OutlierRequest struct {
+	Date     string
+	From, To string
+	Ticker   string // Vazio considera todos os instrumentos
+	Kind     string // Tipo de marcação (entity.OutlierKinds); vazio considera todos
+	Session  string // Tipo de sessão; vazio considera todas
+	Limit    string
+}
+
+// validateOutlierRules rejeita critérios negativos e janelas que não permitem calcular
+// o desvio padrão.
+func validateOutlierRules(rules repository.OutlierRules) error {
+	if rules.BlockMultiple < 0 || rules.DeviationPct < 0 || rules.DeviationSigma < 0 {
+		return fmt.Errorf("critérios de outliers não podem ser negativos")
+	}
+	if rules.HistoryDays < 1 {
+		return fmt.Errorf("a janela do tamanho médio deve ter ao menos 1 dia")
+	}
+	if rules.PriceWindow < 2 {
+		return fmt.Errorf("a janela da média móvel de preço deve ter ao menos 2 negócios")
+	}
+	return nil
+}
+
+// DetectOutliers executa a detecção de outliers com rules para as datas de 'trades' no
+// intervalo [from, to] (datas zeradas não limitam o intervalo), substituindo as
+// marcações anteriores de cada data. Retorna as datas processadas.
+func (s *tradeServiceImpl) DetectOutliers(ctx context.Context, from, to time.Time, rules repository.OutlierRules) ([]time.Time, error) {
+	if err := validateOutlierRules(rules); err != nil {
+		return nil, fmt.Errorf("service: %w", err)
+	}
+	dates, err := s.tradeRepo.ListTradeDates(ctx, from, to, false)
+	if err != nil {
+		return nil, fmt.Errorf("service: %w", err)
+	}
+	return s.detectOutliers(ctx, dates, rules)
+}
+
+// detectOutliers executa a detecção para cada data, na ordem recebida.
+func (s *tradeServiceImpl) detectOutliers(ctx context.Context, dates []time.Time, rules repository.OutlierRules) ([]time.Time, error) {
+	detected := make([]time.Time, 0, len(dates))
+	for _, date := range dates {
+		count, err := s.tradeRepo.DetectOutliers(ctx, date, rules)
+		if err != nil {
+			return detected, fmt.Errorf("service: falha ao detectar outliers de %s: %w", date.Format("2006-01-02"), err)
+		}
+		logger.Info("Outliers detectados",
+			zap.String("trade_date", date.Format("2006-01-02")),
+			zap.Int64("outliers", count))
+		detected = append(detected, date)
+	}
+	return detected, nil
+}
+
+// RetrieveOutliers lista as negociações marcadas pela detecção de outliers no pregão
+// ou intervalo pedido, opcionalmente de um instrumento, de um tipo de marcação e de uma
+// sessão.
+func (s *tradeServiceImpl) RetrieveOutliers(ctx context.Context, req OutlierRequest) (*entity.OutlierReport, error) {
+	r, err := s.parseDayOrRange(req.Date, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	if req.Kind != "" && !slices.Contains(entity.OutlierKinds, req.Kind) {
+		return nil, invalidParameter("'kind' inválido ('%s'). Use %s", req.Kind, strings.Join(entity.OutlierKinds, ", "))
+	}
+	session, err := parseSession(req.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := defaultOutlierLimit
+	if req.Limit != "" {
+		n, err := strconv.Atoi(req.Limit)
+		if err != nil || n < 1 || n > maxOutlierLimit {
+			return nil, invalidParameter("'limit' deve ser um inteiro entre 1 e %d", maxOutlierLimit)
+		}
+		limit = n
+	}
+
+	outliers, err := s.tradeRepo.ListOutliers(ctx, repository.OutlierQuery{
+		From: r.From, To: r.To, InstrumentCode: req.Ticker, Kind: req.Kind, Session: session, Limit: limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao buscar outliers: %w", err)
+	}
+
+	return &entity.OutlierReport{From: r.From, To: r.To, Ticker: req.Ticker, Kind: req.Kind, Outliers: outliers}, nil
+}
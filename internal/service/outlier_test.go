@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+func TestRetrieveOutliersSession(t *testing.T) {
+	tests := []struct {
+		name    string
+		session string
+		want    *int
+		wantErr bool
+	}{
+		{"todas as sessões", "", nil, false},
+		{"after-market pelo nome", "after_market", intPtr(entity.SessionAfterMarket), false},
+		{"regular pelo código", "1", intPtr(entity.SessionRegular), false},
+		{"sessão inválida", "noturna", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTradeRepository{}
+			svc := NewTradeService(nil, repo, nil, IngestionOptions{})
+			_, err := svc.RetrieveOutliers(context.Background(), OutlierRequest{Date: "2024-01-02", Session: tt.session})
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidParameter) {
+					t.Fatalf("RetrieveOutliers: erro %v, esperado ErrInvalidParameter", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RetrieveOutliers: %v", err)
+			}
+			got := repo.outlierQuery.Session
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("sessão consultada = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
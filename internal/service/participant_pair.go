@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+)
+
+// RetrieveParticipantPairs monta o relatório de vigilância do instrumento em um pregão
+// (dateStr, YYYY-MM-DD): a matriz de pares comprador x vendedor e as negociações em que
+// comprador e vendedor são o mesmo participante. sessionStr restringe a um tipo de sessão.
+func (s *tradeServiceImpl) RetrieveParticipantPairs(ctx context.Context, instrumentCode, dateStr, sessionStr string) (*entity.ParticipantPairReport, error) {
+	tradeDate, err := s.parseTradeDate("date", dateStr)
+	if err != nil {
+		return nil, err
+	}
+	session, err := parseSession(sessionStr)
+	if err != nil {
+		return nil, err
+	}
+	q := repository.TradeQuery{InstrumentCode: instrumentCode, From: tradeDate, To: tradeDate, Session: session}
+
+	pairs, err := s.tradeRepo.GetParticipantPairs(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao calcular pares de participantes: %w", err)
+	}
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("service: dados não encontrados para %s em %s", instrumentCode, dateStr)
+	}
+
+	selfTrades, err := s.tradeRepo.ListSelfTrades(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao listar self-trades: %w", err)
+	}
+
+	report := &entity.ParticipantPairReport{
+		InstrumentCode: instrumentCode, TradeDate: tradeDate,
+		Pairs: pairs, SelfTrades: selfTrades,
+	}
+	for _, p := range pairs {
+		report.TradeCount += p.TradeCount
+		report.Volume += p.Volume
+		if p.SelfTrade {
+			report.SelfTradeCount += p.TradeCount
+			report.SelfTradeVolume += p.Volume
+		}
+	}
+	for i := range pairs {
+		pairs[i].VolumeShare = roundTo(share(pairs[i].Volume, report.Volume), 4)
+	}
+
+	return report, nil
+}
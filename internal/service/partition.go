@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+)
+
+// PartitionService aplica a política de retenção sobre as partições mensais de 'trades'.
+type PartitionService interface {
+	ListPartitions(ctx context.Context) ([]entity.TradePartition, error)
+	PrunePartitions(ctx context.Context, retentionMonths int, dryRun bool) ([]entity.TradePartition, error)
+}
+
+type partitionServiceImpl struct {
+	partitionRepo repository.PartitionRepository
+	now           func() time.Time
+}
+
+func NewPartitionService(repo repository.PartitionRepository) PartitionService {
+	return &partitionServiceImpl{
+		partitionRepo: repo,
+		now:           time.Now,
+	}
+}
+
+// ListPartitions retorna as partições existentes de 'trades'.
+func (s *partitionServiceImpl) ListPartitions(ctx context.Context) ([]entity.TradePartition, error) {
+	partitions, err := s.partitionRepo.ListTradePartitions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao listar partições: %w", err)
+	}
+	return partitions, nil
+}
+
+// PrunePartitions desanexa e remove as partições que terminam antes da janela de
+// retenção. Com retenção de N meses são mantidos o mês corrente e os N-1 anteriores.
+// Em modo dryRun nada é removido; as partições que seriam removidas são retornadas.
+func (s *partitionServiceImpl) PrunePartitions(ctx context.Context, retentionMonths int, dryRun bool) ([]entity.TradePartition, error) {
+	if retentionMonths <= 0 {
+		return nil, fmt.Errorf("service: retenção deve ser de pelo menos 1 mês, recebido %d", retentionMonths)
+	}
+
+	now := s.now()
+	cutoff := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(retentionMonths - 1), 0)
+
+	partitions, err := s.partitionRepo.ListTradePartitions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao listar partições: %w", err)
+	}
+
+	var expired []entity.TradePartition
+	for _, partition := range partitions {
+		if partition.To.After(cutoff) {
+			continue
+		}
+		if !dryRun {
+			if err := s.partitionRepo.DropTradePartition(ctx, partition.Name); err != nil {
+				return expired, fmt.Errorf("service: falha ao remover partição expirada: %w", err)
+			}
+		}
+		expired = append(expired, partition)
+	}
+
+	return expired, nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+)
+
+// RetrievePriceBenchmarks calcula VWAP e TWAP do instrumento entre 'from' e 'to'
+// (inclusivos, YYYY-MM-DD) e, opcionalmente, apenas na janela intradiária
+// [startTime, endTime) de cada pregão (HH:MM ou HH:MM:SS), ex: 10:00 a 16:55, e de um
+// tipo de sessão. As datas seguem a regra comum de parseDateRange.
+func (s *tradeServiceImpl) RetrievePriceBenchmarks(ctx context.Context, instrumentCode, fromStr, toStr, startTimeStr, endTimeStr, sessionStr string) (*entity.PriceBenchmark, error) {
+	r, err := s.parseDateRange("from", fromStr, "to", toStr)
+	if err != nil {
+		return nil, err
+	}
+	session, err := parseSession(sessionStr)
+	if err != nil {
+		return nil, err
+	}
+
+	q := repository.TradeQuery{InstrumentCode: instrumentCode, From: r.From, To: r.To, Session: session}
+	if q.FromTime, err = parseClockParam("start_time", startTimeStr); err != nil {
+		return nil, err
+	}
+	if q.ToTime, err = parseClockParam("end_time", endTimeStr); err != nil {
+		return nil, err
+	}
+	if q.FromTime != "" && q.ToTime != "" && q.FromTime >= q.ToTime {
+		return nil, invalidParameter("'start_time' deve ser anterior a 'end_time'")
+	}
+
+	result, err := s.tradeRepo.GetPriceBenchmarks(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao calcular VWAP/TWAP: %w", err)
+	}
+	result.StartTime, result.EndTime = clockFromClosingTime(q.FromTime), clockFromClosingTime(q.ToTime)
+	return result, nil
+}
+
+// parseClockParam converte um horário HH:MM ou HH:MM:SS para o formato de closing_time
+// ("HHMMSSmmm"). Vazio retorna vazio (sem limite).
+func parseClockParam(name, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("150405") + "000", nil
+		}
+	}
+	return "", invalidParameter("formato de '%s' inválido ('%s'). Use HH:MM ou HH:MM:SS", name, value)
+}
+
+// clockFromClosingTime converte "HHMMSSmmm" para HH:MM:SS. Vazio retorna vazio.
+func clockFromClosingTime(closingTime string) string {
+	if len(closingTime) < 6 {
+		return ""
+	}
+	return closingTime[0:2] + ":" + closingTime[2:4] + ":" + closingTime[4:6]
+}
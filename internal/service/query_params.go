@@ -0,0 +1,148 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// ErrInvalidParameter indica um parâmetro de consulta inválido. Os handlers o
+// traduzem em 400 Bad Request.
+var ErrInvalidParameter = errors.New("parâmetro inválido")
+
+const (
+	// MaxQueryRangeDays é o maior intervalo, em dias corridos, aceito pelas consultas.
+	MaxQueryRangeDays = 366
+	// defaultTradingDays é o número de pregões da janela usada quando a data inicial é omitida.
+	defaultTradingDays = 7
+)
+
+// DateRange é um intervalo de datas de pregão com ambos os extremos inclusivos.
+type DateRange struct {
+	From, To time.Time
+}
+
+// invalidParameter cria um erro que envolve ErrInvalidParameter.
+func invalidParameter(format string, args ...any) error {
+	return fmt.Errorf("service: %w: %s", ErrInvalidParameter, fmt.Sprintf(format, args...))
+}
+
+// today retorna a data corrente no fuso da B3, à meia-noite UTC como as datas de 'trades'.
+func (s *tradeServiceImpl) today() time.Time {
+	now := s.now().In(entity.B3Location)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// parseSession interpreta o parâmetro 'session': o nome (regular, after_market,
+// unknown) ou o código numérico de TipoSessaoPregao. Vazio retorna nil (todas as sessões).
+func parseSession(value string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	session, ok := entity.ParseSession(value)
+	if !ok {
+		return nil, invalidParameter("'session' inválido ('%s'). Use regular, after_market, unknown ou o código numérico da sessão", value)
+	}
+	return &session, nil
+}
+
+// parseDate interpreta uma data YYYY-MM-DD.
+func parseDate(name, value string) (time.Time, error) {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, invalidParameter("formato de '%s' inválido ('%s'). Use YYYY-MM-DD", name, value)
+	}
+	return date, nil
+}
+
+// warnUncovered registra quando datas derivadas do calendário caem em anos ausentes do
+// arquivo de feriados: nesses anos os feriados não são conhecidos e a janela padrão
+// pode incluir dias sem pregão.
+func (s *tradeServiceImpl) warnUncovered(from, to time.Time) {
+	if uncovered := s.calendar.Uncovered(from, to); len(uncovered) > 0 {
+		logger.Info("⚠️ Calendário de feriados não cobre o período; considerando apenas fins de semana",
+			zap.Ints("years", uncovered),
+			zap.Ints("covered_years", s.calendar.Years()),
+		)
+	}
+}
+
+// parseDateRange aplica a regra comum de intervalo das consultas: datas YYYY-MM-DD,
+// extremos inclusivos, início não posterior ao fim, nenhuma data no futuro e no
+// máximo MaxQueryRangeDays dias. Sem início, a janela são os últimos
+// defaultTradingDays pregões do calendário da B3 terminando no fim informado ou,
+// sem fim, no pregão anterior a hoje. Com início e sem fim, o fim é o dia atual.
+func (s *tradeServiceImpl) parseDateRange(fromName, fromStr, toName, toStr string) (DateRange, error) {
+	today := s.today()
+	r := DateRange{To: today}
+
+	var err error
+	if toStr != "" {
+		if r.To, err = parseDate(toName, toStr); err != nil {
+			return DateRange{}, err
+		}
+	}
+	if fromStr != "" {
+		if r.From, err = parseDate(fromName, fromStr); err != nil {
+			return DateRange{}, err
+		}
+	} else {
+		end := r.To
+		if toStr == "" {
+			end = s.calendar.PreviousTradingDay(today)
+		}
+		r.From, r.To = s.calendar.LastTradingDays(end, defaultTradingDays)
+		s.warnUncovered(r.From, r.To)
+	}
+
+	switch {
+	case r.To.After(today):
+		return DateRange{}, invalidParameter("'%s' (%s) não pode ser uma data futura", toName, r.To.Format("2006-01-02"))
+	case r.From.After(r.To):
+		return DateRange{}, invalidParameter("'%s' (%s) é posterior a '%s' (%s)", fromName, r.From.Format("2006-01-02"), toName, r.To.Format("2006-01-02"))
+	case r.To.Sub(r.From) >= MaxQueryRangeDays*24*time.Hour:
+		return DateRange{}, invalidParameter("intervalo entre '%s' e '%s' excede %d dias", fromName, toName, MaxQueryRangeDays)
+	}
+	return r, nil
+}
+
+// parseTradeDate valida a data de um único pregão: obrigatória, YYYY-MM-DD e não futura.
+func (s *tradeServiceImpl) parseTradeDate(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, invalidParameter("'%s' é obrigatório no formato YYYY-MM-DD", name)
+	}
+	date, err := parseDate(name, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if date.After(s.today()) {
+		return time.Time{}, invalidParameter("'%s' (%s) não pode ser uma data futura", name, value)
+	}
+	return date, nil
+}
+
+// parseDayOrRange aceita um único pregão ('date') ou um intervalo ('from'/'to', pela
+// regra de parseDateRange), mas não ambos. Sem nenhum deles, usa o pregão anterior a hoje.
+func (s *tradeServiceImpl) parseDayOrRange(dateStr, fromStr, toStr string) (DateRange, error) {
+	switch {
+	case dateStr != "" && (fromStr != "" || toStr != ""):
+		return DateRange{}, invalidParameter("use 'date' ou 'from'/'to', não ambos")
+	case dateStr != "":
+		date, err := s.parseTradeDate("date", dateStr)
+		if err != nil {
+			return DateRange{}, err
+		}
+		return DateRange{From: date, To: date}, nil
+	case fromStr != "" || toStr != "":
+		return s.parseDateRange("from", fromStr, "to", toStr)
+	default:
+		previous := s.calendar.PreviousTradingDay(s.today())
+		s.warnUncovered(previous, previous)
+		return DateRange{From: previous, To: previous}, nil
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+)
+
+const (
+	defaultRankingLimit = 10
+	maxRankingLimit     = 100
+)
+
+// RankingRequest reúne os parâmetros de RetrieveRankings como recebidos na API.
+// 'Date' consulta um único pregão; 'From'/'To' um intervalo. Sem nenhum deles, usa o
+// pregão anterior a hoje.
+type RankingRequest struct {
+	Date       string
+	From, To   string
+	Metric     string
+	Limit      string
+	AssetClass string
+	Session    string // Tipo de sessão; vazio considera todas
+}
+
+// RetrieveRankings lista os instrumentos com maiores e menores valores da métrica no
+// pregão ou intervalo informado, opcionalmente filtrando por classe de ativo.
+func (s *tradeServiceImpl) RetrieveRankings(ctx context.Context, req RankingRequest) (*entity.Ranking, error) {
+	if _, ok := repository.RankingMetrics[req.Metric]; !ok {
+		metrics := make([]string, 0, len(repository.RankingMetrics))
+		for metric := range repository.RankingMetrics {
+			metrics = append(metrics, metric)
+		}
+		sort.Strings(metrics)
+		return nil, invalidParameter("'metric' inválido ('%s'). Use %s", req.Metric, strings.Join(metrics, ", "))
+	}
+
+	limit := defaultRankingLimit
+	if req.Limit != "" {
+		n, err := strconv.Atoi(req.Limit)
+		if err != nil || n < 1 || n > maxRankingLimit {
+			return nil, invalidParameter("'limit' deve ser um inteiro entre 1 e %d", maxRankingLimit)
+		}
+		limit = n
+	}
+
+	if req.AssetClass != "" && !entity.IsAssetClass(req.AssetClass) {
+		return nil, invalidParameter("'asset_class' inválido ('%s')", req.AssetClass)
+	}
+
+	session, err := parseSession(req.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.parseDayOrRange(req.Date, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	top, bottom, err := s.tradeRepo.GetRankings(ctx, repository.RankingQuery{
+		From: r.From, To: r.To, Metric: req.Metric, AssetClass: req.AssetClass, Limit: limit, Session: session,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao calcular ranking: %w", err)
+	}
+	if len(top) == 0 {
+		return nil, fmt.Errorf("service: dados não encontrados entre %s e %s", r.From.Format("2006-01-02"), r.To.Format("2006-01-02"))
+	}
+
+	return &entity.Ranking{
+		Metric: req.Metric, From: r.From, To: r.To, AssetClass: req.AssetClass,
+		Top: top, Bottom: bottom,
+	}, nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+)
+
+const (
+	defaultScreenerPageSize      = 50
+	maxScreenerPageSize          = 500
+	defaultScreenerVolumeAvgDays = 20
+	maxScreenerVolumeAvgDays     = 250
+	maxScreenerFilters           = 20
+)
+
+// ScreenerFilter é uma condição do screener como recebida na API. Operadores de
+// comparação (=, !=, >, >=, <, <=) usam 'value'; between usa 'values' com [mínimo,
+// máximo] e in usa 'values' com a lista aceita.
+type ScreenerFilter struct {
+	Field  string `json:"field"`
+	Op     string `json:"op"`
+	Value  any    `json:"value,omitempty"`
+	Values []any  `json:"values,omitempty"`
+}
+
+// ScreenerSort é um critério de ordenação do screener; 'order' é asc ou desc (padrão).
+type ScreenerSort struct {
+	Field string `json:"field"`
+	Order string `json:"order,omitempty"`
+}
+
+// ScreenerRequest reúne os parâmetros de RunScreener como recebidos na API. Os filtros
+// são combinados com AND; sem 'date', usa o pregão anterior a hoje.
+type ScreenerRequest struct {
+	Date          string           `json:"date,omitempty"`
+	Filters       []ScreenerFilter `json:"filters"`
+	VolumeAvgDays int              `json:"volume_avg_days,omitempty"`
+	Sort          []ScreenerSort   `json:"sort,omitempty"`
+	Page          int              `json:"page,omitempty"`
+	PageSize      int              `json:"page_size,omitempty"`
+	Session       string           `json:"session,omitempty"` // Tipo de sessão; vazio considera todas
+}
+
+// RunScreener aplica os filtros às métricas diárias de todos os instrumentos no pregão
+// e retorna a página solicitada, na ordem pedida (padrão: maior volume financeiro).
+func (s *tradeServiceImpl) RunScreener(ctx context.Context, req ScreenerRequest) (*entity.ScreenerResult, error) {
+	q := repository.ScreenerQuery{VolumeAvgDays: req.VolumeAvgDays}
+
+	if req.Date != "" {
+		date, err := s.parseTradeDate("date", req.Date)
+		if err != nil {
+			return nil, err
+		}
+		q.Date = date
+	} else {
+		q.Date = s.calendar.PreviousTradingDay(s.today())
+		s.warnUncovered(q.Date, q.Date)
+	}
+
+	session, err := parseSession(req.Session)
+	if err != nil {
+		return nil, err
+	}
+	q.Session = session
+
+	if q.VolumeAvgDays == 0 {
+		q.VolumeAvgDays = defaultScreenerVolumeAvgDays
+	}
+	if q.VolumeAvgDays < 1 || q.VolumeAvgDays > maxScreenerVolumeAvgDays {
+		return nil, invalidParameter("'volume_avg_days' deve estar entre 1 e %d", maxScreenerVolumeAvgDays)
+	}
+
+	page, pageSize := req.Page, req.PageSize
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = defaultScreenerPageSize
+	}
+	if page < 1 {
+		return nil, invalidParameter("'page' deve ser maior que zero")
+	}
+	if pageSize < 1 || pageSize > maxScreenerPageSize {
+		return nil, invalidParameter("'page_size' deve estar entre 1 e %d", maxScreenerPageSize)
+	}
+	q.Limit, q.Offset = pageSize, (page-1)*pageSize
+
+	if len(req.Filters) > maxScreenerFilters {
+		return nil, invalidParameter("no máximo %d filtros por consulta, recebidos %d", maxScreenerFilters, len(req.Filters))
+	}
+	for i, f := range req.Filters {
+		filter, err := parseScreenerFilter(f)
+		if err != nil {
+			return nil, invalidParameter("filters[%d]: %v", i, err)
+		}
+		q.Filters = append(q.Filters, filter)
+	}
+
+	for i, sortBy := range req.Sort {
+		if _, ok := repository.ScreenerFields[sortBy.Field]; !ok {
+			return nil, invalidParameter("sort[%d]: campo inválido ('%s'). Use %s", i, sortBy.Field, screenerFieldNames())
+		}
+		switch strings.ToLower(sortBy.Order) {
+		case "", "desc":
+			q.Sort = append(q.Sort, repository.ScreenerSort{Field: sortBy.Field, Descending: true})
+		case "asc":
+			q.Sort = append(q.Sort, repository.ScreenerSort{Field: sortBy.Field})
+		default:
+			return nil, invalidParameter("sort[%d]: 'order' deve ser asc ou desc", i)
+		}
+	}
+	if len(q.Sort) == 0 {
+		q.Sort = []repository.ScreenerSort{{Field: "financial_volume", Descending: true}}
+	}
+
+	rows, total, err := s.tradeRepo.ScreenDailyMetrics(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao executar screener: %w", err)
+	}
+
+	return &entity.ScreenerResult{
+		Date: q.Date, Page: page, PageSize: pageSize, Total: total, Results: rows,
+	}, nil
+}
+
+// parseScreenerFilter valida campo, operador e valores de um filtro, convertendo os
+// valores para o tipo esperado pela coluna (número, ou classe de ativo em asset_class).
+func parseScreenerFilter(f ScreenerFilter) (repository.ScreenerFilter, error) {
+	if _, ok := repository.ScreenerFields[f.Field]; !ok {
+		return repository.ScreenerFilter{}, fmt.Errorf("campo inválido ('%s'). Use %s", f.Field, screenerFieldNames())
+	}
+
+	op := strings.ToLower(f.Op)
+	var raw []any
+	switch op {
+	case "=", "!=", ">", ">=", "<", "<=":
+		if f.Value == nil || len(f.Values) > 0 {
+			return repository.ScreenerFilter{}, fmt.Errorf("operador '%s' exige 'value'", op)
+		}
+		raw = []any{f.Value}
+	case "between":
+		if f.Value != nil || len(f.Values) != 2 {
+			return repository.ScreenerFilter{}, fmt.Errorf("operador between exige 'values' com [mínimo, máximo]")
+		}
+		raw = f.Values
+	case "in":
+		if f.Value != nil || len(f.Values) == 0 {
+			return repository.ScreenerFilter{}, fmt.Errorf("operador in exige 'values' com ao menos um item")
+		}
+		raw = f.Values
+	default:
+		return repository.ScreenerFilter{}, fmt.Errorf("operador inválido ('%s'). Use =, !=, >, >=, <, <=, between ou in", f.Op)
+	}
+
+	if f.Field == "asset_class" {
+		if op != "=" && op != "!=" && op != "in" {
+			return repository.ScreenerFilter{}, fmt.Errorf("asset_class aceita apenas =, != e in")
+		}
+		classes := make([]string, len(raw))
+		for i, value := range raw {
+			class, ok := value.(string)
+			if !ok || !entity.IsAssetClass(class) {
+				return repository.ScreenerFilter{}, fmt.Errorf("classe de ativo inválida (%v)", value)
+			}
+			classes[i] = class
+		}
+		if op == "in" {
+			return repository.ScreenerFilter{Field: f.Field, Op: op, Values: []any{classes}}, nil
+		}
+		return repository.ScreenerFilter{Field: f.Field, Op: op, Values: []any{classes[0]}}, nil
+	}
+
+	numbers := make([]float64, len(raw))
+	for i, value := range raw {
+		n, ok := value.(float64)
+		if !ok {
+			return repository.ScreenerFilter{}, fmt.Errorf("'%s' exige valores numéricos, recebido %v", f.Field, value)
+		}
+		numbers[i] = n
+	}
+	switch op {
+	case "in":
+		return repository.ScreenerFilter{Field: f.Field, Op: op, Values: []any{numbers}}, nil
+	case "between":
+		if numbers[0] > numbers[1] {
+			return repository.ScreenerFilter{}, fmt.Errorf("between exige mínimo menor ou igual ao máximo")
+		}
+		return repository.ScreenerFilter{Field: f.Field, Op: op, Values: []any{numbers[0], numbers[1]}}, nil
+	default:
+		return repository.ScreenerFilter{Field: f.Field, Op: op, Values: []any{numbers[0]}}, nil
+	}
+}
+
+// screenerFieldNames lista os campos aceitos pelo screener, em ordem alfabética.
+func screenerFieldNames() string {
+	names := make([]string, 0, len(repository.ScreenerFields))
+	for name := range repository.ScreenerFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+)
+
+// RetrieveSessionBreakdown divide as negociações do instrumento entre 'from' e 'to' por
+// tipo de sessão (TipoSessaoPregao), com volume e amplitude de preço de cada uma. As
+// datas seguem a regra comum de parseDateRange.
+func (s *tradeServiceImpl) RetrieveSessionBreakdown(ctx context.Context, instrumentCode, fromStr, toStr string) (*entity.SessionBreakdown, error) {
+	r, err := s.parseDateRange("from", fromStr, "to", toStr)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.tradeRepo.GetSessionBreakdown(ctx, repository.TradeQuery{InstrumentCode: instrumentCode, From: r.From, To: r.To})
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao calcular negociações por sessão: %w", err)
+	}
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("service: dados não encontrados para %s entre %s e %s",
+			instrumentCode, r.From.Format("2006-01-02"), r.To.Format("2006-01-02"))
+	}
+
+	var volume int64
+	for _, session := range sessions {
+		volume += session.Volume
+	}
+	for i := range sessions {
+		sessions[i].Name = entity.SessionName(sessions[i].Session)
+		sessions[i].Range = roundTo(sessions[i].High-sessions[i].Low, 4)
+		sessions[i].FinancialVolume = roundTo(sessions[i].FinancialVolume, 4)
+		sessions[i].VolumeShare = roundTo(share(sessions[i].Volume, volume), 4)
+	}
+
+	return &entity.SessionBreakdown{InstrumentCode: instrumentCode, From: r.From, To: r.To, Sessions: sessions}, nil
+}
@@ -3,9 +3,9 @@ package service
 import (
 	"context"
 	"fmt"
-	"sync"
 	"time"
 
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/calendar"
 	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
 	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/ingestion"
 	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
@@ -19,109 +19,95 @@ type ProgressTracker interface {
 type TradeService interface {
 	ProcessIngestion(ctx context.Context, filePath string) error
 	ProcessIngestionWithProgress(ctx context.Context, filePath string, progressTracker ProgressTracker) error
-	RetrieveAggregatedData(ctx context.Context, instrumentCode string, startDateStr string) (*entity.AggregatedData, error)
+	RebuildDailySummaries(ctx context.Context, from, to time.Time, missingOnly bool) ([]time.Time, error)
+	DetectOutliers(ctx context.Context, from, to time.Time, rules repository.OutlierRules) ([]time.Time, error)
+	CheckTradeIDs(ctx context.Context, from, to time.Time) ([]entity.TradeIDCheck, error)
+	VerifyIngestion(ctx context.Context, filePath string) (*entity.LoadVerification, error)
+	RetrieveAggregatedData(ctx context.Context, instrumentCode string, startDateStr, endDateStr, sessionStr string, withStatistics bool) (*entity.AggregatedData, error)
+	RetrieveAggregatedDataBatch(ctx context.Context, requests []AggregatedRequest, sessionStr string) ([]entity.AggregatedBatchItem, error)
+	RetrieveDailySeries(ctx context.Context, instrumentCode string, fromStr, toStr, sessionStr string) (*entity.DailySeries, error)
+	RetrieveCandles(ctx context.Context, instrumentCode, interval, dateStr, sessionStr string, fill, regularOnly bool) (*entity.BarSeries, error)
+	RetrieveEventBars(ctx context.Context, instrumentCode, barType, thresholdStr, dateStr, sessionStr string) (*entity.BarSeries, error)
+	RetrieveRankings(ctx context.Context, req RankingRequest) (*entity.Ranking, error)
+	RunScreener(ctx context.Context, req ScreenerRequest) (*entity.ScreenerResult, error)
+	RetrievePriceBenchmarks(ctx context.Context, instrumentCode, fromStr, toStr, startTimeStr, endTimeStr, sessionStr string) (*entity.PriceBenchmark, error)
+	RetrieveBrokerFlows(ctx context.Context, instrumentCode, fromStr, toStr, limitStr, sessionStr string) (*entity.BrokerFlow, error)
+	RetrieveParticipantPairs(ctx context.Context, instrumentCode, dateStr, sessionStr string) (*entity.ParticipantPairReport, error)
+	RetrieveSessionBreakdown(ctx context.Context, instrumentCode, fromStr, toStr string) (*entity.SessionBreakdown, error)
+	RetrieveVolumeProfile(ctx context.Context, instrumentCode string, req VolumeProfileRequest) (*entity.VolumeProfile, error)
+	RetrieveVolatility(ctx context.Context, instrumentCode, fromStr, toStr, windowStr, sessionStr string) (*entity.VolatilityReport, error)
+	RetrieveIndicators(ctx context.Context, instrumentCode string, req IndicatorRequest) (*entity.IndicatorSeries, error)
+	RetrieveOutliers(ctx context.Context, req OutlierRequest) (*entity.OutlierReport, error)
 }
 
 type tradeServiceImpl struct {
 	tradeReader ingestion.TradeReader
 	tradeRepo   repository.TradeRepository
+	calendar    *calendar.Calendar
+	opts        IngestionOptions
+	now         func() time.Time
 }
 
-func NewTradeService(reader ingestion.TradeReader, repo repository.TradeRepository) TradeService {
+// NewTradeService cria o serviço de negociações. Sem calendário (nil), usa o
+// calendário de pregões embutido.
+func NewTradeService(reader ingestion.TradeReader, repo repository.TradeRepository, cal *calendar.Calendar, opts IngestionOptions) TradeService {
+	if cal == nil {
+		cal = calendar.MustDefault()
+	}
 	return &tradeServiceImpl{
 		tradeReader: reader,
 		tradeRepo:   repo,
+		calendar:    cal,
+		opts:        opts.WithDefaults(),
+		now:         time.Now,
 	}
 }
 
-// ProcessIngestion orquestra o pipeline de leitura, parsing e persistência.
-func (s *tradeServiceImpl) ProcessIngestion(ctx context.Context, filePath string) error {
-	return s.ProcessIngestionWithProgress(ctx, filePath, nil)
-}
-
-// ProcessIngestionWithProgress orquestra o pipeline de leitura, parsing e persistência com tracking de progresso.
-func (s *tradeServiceImpl) ProcessIngestionWithProgress(ctx context.Context, filePath string, progressTracker ProgressTracker) error {
-	// Check if reader is available (for web app that doesn't need ingestion)
-	if s.tradeReader == nil {
-		return fmt.Errorf("service: trade reader not available - ingestion not supported in this context")
-	}
-
-	const (
-		numWorkers = 4    // Número de goroutines para processar e salvar (ajustável)
-		batchSize  = 1000 // Tamanho do lote para inserção no banco de dados
-	)
-
-	tradeCh := s.tradeReader.Read(ctx, filePath)
-
-	var wg sync.WaitGroup
-	errCh := make(chan error, numWorkers) // Canal para coletar erros dos workers
-
-	// Iniciar workers consumidores
-	for i := 0; i < numWorkers; i++ {
-		wg.Add(1)
-		go func(workerID int) {
-			defer wg.Done()
-			batch := make([]entity.Trade, 0, batchSize)
-			for trade := range tradeCh {
-				batch = append(batch, trade)
-
-				// Increment progress tracker if available
-				if progressTracker != nil {
-					progressTracker.Increment()
-				}
-
-				if len(batch) >= batchSize {
-					// Salvar lote no banco de dados
-					err := s.tradeRepo.SaveTrades(ctx, batch)
-					if err != nil {
-						errCh <- fmt.Errorf("worker %d: falha ao salvar lote: %w", workerID, err)
-						return // Termina o worker em caso de erro fatal de DB
-					}
-					batch = make([]entity.Trade, 0, batchSize) // Reseta o lote
-				}
-			}
-			// Salvar qualquer lote restante no final do canal
-			if len(batch) > 0 {
-				err := s.tradeRepo.SaveTrades(ctx, batch)
-				if err != nil {
-					errCh <- fmt.Errorf("worker %d: falha ao salvar lote final: %w", workerID, err)
-				}
-			}
-		}(i)
+// RetrieveAggregatedData obtém dados agregados no intervalo [data_inicio, data_fim],
+// validado pela regra comum de parseDateRange, opcionalmente apenas com as negociações
+// de um tipo de sessão (sessionStr; vazio considera todas).
+// Com withStatistics, inclui o bloco completo de estatísticas do período.
+func (s *tradeServiceImpl) RetrieveAggregatedData(ctx context.Context, instrumentCode string, startDateStr, endDateStr, sessionStr string, withStatistics bool) (*entity.AggregatedData, error) {
+	r, err := s.parseDateRange("data_inicio", startDateStr, "data_fim", endDateStr)
+	if err != nil {
+		return nil, err
 	}
-
-	wg.Wait()    // Espera todos os workers terminarem
-	close(errCh) // Fecha o canal de erros após todos os workers terminarem
-
-	// Verifica se houve algum erro nos workers
-	for err := range errCh {
-		return err // Retorna o primeiro erro encontrado
+	session, err := parseSession(sessionStr)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
-}
-
-// RetrieveAggregatedData obtém dados agregados e aplica a lógica de cálculo da data.
-func (s *tradeServiceImpl) RetrieveAggregatedData(ctx context.Context, instrumentCode string, startDateStr string) (*entity.AggregatedData, error) {
-	var startDate time.Time
-	var err error
-
-	if startDateStr != "" {
-		startDate, err = time.Parse("2006-01-02", startDateStr) // Formato ISO-8601
+	if withStatistics {
+		data, err := s.tradeRepo.GetAggregatedStatistics(ctx, instrumentCode, r.From, r.To, session)
 		if err != nil {
-			return nil, fmt.Errorf("service: formato de 'data_inicio' inválido. Use YYYY-MM-DD: %w", err)
+			return nil, fmt.Errorf("service: falha ao buscar estatísticas agregadas: %w", err)
 		}
-	} else {
-		// Se data_inicio for omitido, a consulta deve abranger os últimos 7 dias úteis,
-		// tendo como último dia do período de análise a data anterior à atual.
-		// Exemplo simplificado: hoje - 8 dias para cobrir 7 dias úteis "completos".
-		// Uma implementação mais robusta envolveria um calendário de dias úteis da B3.
-		startDate = time.Now().AddDate(0, 0, -8) // Ajuste conforme a regra exata de "dias úteis"
+		return data, nil
 	}
 
-	data, err := s.tradeRepo.GetAggregatedData(ctx, instrumentCode, startDate)
+	data, err := s.tradeRepo.GetAggregatedData(ctx, instrumentCode, r.From, r.To, session)
 	if err != nil {
 		return nil, fmt.Errorf("service: falha ao buscar dados agregados: %w", err)
 	}
 	return data, nil
 }
+
+// RetrieveDailySeries obtém a série diária OHLCV do instrumento entre 'from' e 'to'
+// (inclusivos, YYYY-MM-DD), validados pela regra comum de parseDateRange, opcionalmente
+// apenas com as negociações de um tipo de sessão.
+func (s *tradeServiceImpl) RetrieveDailySeries(ctx context.Context, instrumentCode string, fromStr, toStr, sessionStr string) (*entity.DailySeries, error) {
+	r, err := s.parseDateRange("from", fromStr, "to", toStr)
+	if err != nil {
+		return nil, err
+	}
+	session, err := parseSession(sessionStr)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := s.tradeRepo.GetDailySeries(ctx, instrumentCode, r.From, r.To, session)
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao buscar série diária: %w", err)
+	}
+	return &entity.DailySeries{InstrumentCode: instrumentCode, Days: days}, nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+	"go.uber.org/zap"
+)
+
+// tradeIDStep é o incremento de CodigoIdentificadorNegocio entre negócios consecutivos
+// de um instrumento no pregão (10, 20, 30... nos arquivos da B3).
+const tradeIDStep = 10
+
+// CheckTradeIDs verifica a completude das datas de 'trades' no intervalo [from, to]
+// (datas zeradas não limitam o intervalo), substituindo as falhas gravadas de cada data.
+func (s *tradeServiceImpl) CheckTradeIDs(ctx context.Context, from, to time.Time) ([]entity.TradeIDCheck, error) {
+	dates, err := s.tradeRepo.ListTradeDates(ctx, from, to, false)
+	if err != nil {
+		return nil, fmt.Errorf("service: %w", err)
+	}
+	checks, err := s.checkTradeIDs(ctx, repository.TradesTable, dates)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tradeRepo.ReplaceTradeIDIssues(ctx, checks); err != nil {
+		return nil, fmt.Errorf("service: %w", err)
+	}
+	return checks, nil
+}
+
+// checkTradeIDs procura identificadores ausentes e repetidos nas datas de 'table' e
+// retorna a verificação de cada data, sem gravá-la: na ingestão, as falhas só são
+// gravadas junto com a troca das datas (ReplaceTradeDates).
+func (s *tradeServiceImpl) checkTradeIDs(ctx context.Context, table string, dates []time.Time) ([]entity.TradeIDCheck, error) {
+	checks, err := s.tradeRepo.CheckTradeIDs(ctx, table, dates, tradeIDStep)
+	if err != nil {
+		return nil, fmt.Errorf("service: %w", err)
+	}
+
+	for i := range checks {
+		check := &checks[i]
+		// Esperados: identificadores distintos dentro da sequência mais os ausentes.
+		if expected := check.Trades - check.Duplicates - check.Misaligned + check.Missing; expected > 0 {
+			check.MissingPct = roundTo(float64(check.Missing)/float64(expected)*100, 4)
+		}
+		logger.Info("Completude dos identificadores de negócio verificada",
+			zap.String("trade_date", check.TradeDate.Format("2006-01-02")),
+			zap.Int64("trades", check.Trades),
+			zap.Int64("missing", check.Missing),
+			zap.Int64("duplicates", check.Duplicates),
+			zap.Int64("misaligned", check.Misaligned),
+			zap.Float64("missing_pct", check.MissingPct))
+	}
+	return checks, nil
+}
+
+// exceedsGapThreshold retorna um erro para a primeira data com percentual de negócios
+// ausentes acima de maxGapPct. maxGapPct zerado desativa o limite.
+func exceedsGapThreshold(checks []entity.TradeIDCheck, maxGapPct float64) error {
+	if maxGapPct <= 0 {
+		return nil
+	}
+	for _, check := range checks {
+		if check.MissingPct > maxGapPct {
+			return fmt.Errorf("%d negócio(s) ausente(s) em %s (%.4f%%, limite %.4f%%)",
+				check.Missing, check.TradeDate.Format("2006-01-02"), check.MissingPct, maxGapPct)
+		}
+	}
+	return nil
+}
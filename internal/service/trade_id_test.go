@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// O percentual de ausentes considera como esperados os identificadores distintos dentro
+// da sequência mais os ausentes: repetições e identificadores fora do incremento não
+// entram na base.
+func TestCheckTradeIDsMissingPct(t *testing.T) {
+	tests := []struct {
+		name  string
+		check entity.TradeIDCheck
+		want  float64
+	}{
+		{"sem falhas", entity.TradeIDCheck{Trades: 99}, 0},
+		{"sem identificadores", entity.TradeIDCheck{}, 0},
+		// 98 linhas - 1 repetida - 1 fora do incremento + 2 ausentes = 98 esperados.
+		{"com falhas", entity.TradeIDCheck{Trades: 98, Missing: 2, Duplicates: 1, Misaligned: 1}, 2.0408},
+		{"metade ausente", entity.TradeIDCheck{Trades: 10, Missing: 10}, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check.TradeDate = testDate(2)
+			svc := &tradeServiceImpl{tradeRepo: &fakeTradeRepository{idChecks: []entity.TradeIDCheck{tt.check}}}
+			checks, err := svc.checkTradeIDs(context.Background(), "trades", nil)
+			if err != nil {
+				t.Fatalf("checkTradeIDs: %v", err)
+			}
+			if checks[0].MissingPct != tt.want {
+				t.Errorf("missing_pct = %v, esperado %v", checks[0].MissingPct, tt.want)
+			}
+		})
+	}
+}
+
+func TestExceedsGapThreshold(t *testing.T) {
+	checks := []entity.TradeIDCheck{
+		{TradeDate: testDate(2), Missing: 1, MissingPct: 0.5},
+		{TradeDate: testDate(3), Missing: 4, MissingPct: 2},
+	}
+	tests := []struct {
+		name      string
+		maxGapPct float64
+		wantErr   bool
+	}{
+		{"limite desativado", 0, false},
+		{"abaixo do limite", 2, false},
+		{"acima do limite", 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := exceedsGapThreshold(checks, tt.maxGapPct); (err != nil) != tt.wantErr {
+				t.Errorf("exceedsGapThreshold(%v) = %v, esperado erro: %v", tt.maxGapPct, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/ingestion"
+	"go.uber.org/zap"
+)
+
+// Modos de gravação suportados pelo pipeline de ingestão.
+const (
+	IngestionModeStream = "stream" // Um COPY contínuo por worker, com commit a cada CommitInterval linhas
+	IngestionModeBatch  = "batch"  // Uma transação por lote de BatchSize linhas
+)
+
+// IngestionOptions agrupa os parâmetros ajustáveis do pipeline de ingestão.
+// Valores zerados são substituídos pelos padrões em WithDefaults.
+type IngestionOptions struct {
+	Workers        int    // Número de goroutines consumidoras
+	Mode           string // IngestionModeStream ou IngestionModeBatch
+	BatchSize      int    // Tamanho do lote no modo "batch"
+	CommitInterval int    // Linhas por transação no modo "stream"
+	DetectOutliers bool   // Executa a detecção de outliers (DefaultOutlierRules) nas datas carregadas
+	// MaxGapPct é o percentual máximo de negócios ausentes na sequência de identificadores
+	// de uma data; acima dele a ingestão falha. Zerado, as falhas são apenas registradas.
+	MaxGapPct float64
+	Verify    bool // Relê o arquivo após a troca e reconcilia com 'trades' (VerifyIngestion)
+}
+
+// WithDefaults preenche os campos não informados com os valores padrão.
+func (o IngestionOptions) WithDefaults() IngestionOptions {
+	if o.Workers <= 0 {
+		o.Workers = 4
+	}
+	if o.Mode == "" {
+		o.Mode = IngestionModeStream
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 1000
+	}
+	if o.CommitInterval <= 0 {
+		o.CommitInterval = 100000
+	}
+	return o
+}
+
+// ProcessIngestion orquestra o pipeline de leitura, parsing e persistência.
+func (s *tradeServiceImpl) ProcessIngestion(ctx context.Context, filePath string) error {
+	return s.ProcessIngestionWithProgress(ctx, filePath, nil)
+}
+
+// ProcessIngestionWithProgress orquestra o pipeline de leitura, parsing e persistência com tracking de progresso.
+//
+// As negociações são gravadas primeiro em uma tabela de staging UNLOGGED. Depois que o
+// arquivo é lido por completo, os checksums por data calculados durante o streaming são
+// comparados aos da staging e, só então, as datas carregadas são substituídas em 'trades'
+// em uma única transação, junto com os resumos diários (daily_summaries) calculados durante
+// o streaming. Qualquer falha antes da troca deixa 'trades' e 'daily_summaries' intactas.
+//
+// Antes da troca, a sequência de identificadores de negócio da staging é verificada; se
+// alguma data ultrapassar MaxGapPct, a ingestão falha sem trocar as datas e as falhas só
+// aparecem no log. Caso contrário, as falhas são gravadas em trade_id_issues na mesma
+// transação da troca, de modo que sempre descrevem as negociações gravadas. Depois da troca, a carga é registrada em
+// trade_loads e, com Verify, o arquivo é relido e reconciliado com 'trades'; divergências
+// fazem a ingestão falhar, mas as datas já trocadas são mantidas.
+func (s *tradeServiceImpl) ProcessIngestionWithProgress(ctx context.Context, filePath string, progressTracker ProgressTracker) error {
+	// Check if reader is available (for web app that doesn't need ingestion)
+	if s.tradeReader == nil {
+		return fmt.Errorf("service: trade reader not available - ingestion not supported in this context")
+	}
+
+	stagingTable, err := s.tradeRepo.CreateStagingTable(ctx)
+	if err != nil {
+		return fmt.Errorf("service: %w", err)
+	}
+	defer func() {
+		// Usa um contexto próprio para remover a staging mesmo se 'ctx' expirou.
+		dropCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := s.tradeRepo.DropStagingTable(dropCtx, stagingTable); err != nil {
+			logger.Error("Falha ao remover tabela de staging", err, zap.String("table", stagingTable))
+		}
+	}()
+
+	expected, summaries, err := s.loadStaging(ctx, filePath, stagingTable, progressTracker)
+	if err != nil {
+		return err
+	}
+
+	staged, err := s.tradeRepo.GetStagingChecksums(ctx, stagingTable)
+	if err != nil {
+		return fmt.Errorf("service: %w", err)
+	}
+	if err := compareChecksums(expected, staged); err != nil {
+		return fmt.Errorf("service: validação da staging falhou: %w", err)
+	}
+
+	tradeDates, err := checksumDates(expected)
+	if err != nil {
+		return fmt.Errorf("service: %w", err)
+	}
+
+	checks, err := s.checkTradeIDs(ctx, stagingTable, tradeDates)
+	if err != nil {
+		return err
+	}
+	if err := exceedsGapThreshold(checks, s.opts.MaxGapPct); err != nil {
+		return fmt.Errorf("service: verificação de completude falhou: %w", err)
+	}
+	if err := s.tradeRepo.ReplaceTradeDates(ctx, stagingTable, tradeDates, summaries.Summaries(), checks); err != nil {
+		return fmt.Errorf("service: %w", err)
+	}
+
+	// O registro da carga é informativo: uma falha não desfaz a troca já confirmada.
+	tradeCounts := make([]int64, len(tradeDates))
+	for i, date := range tradeDates {
+		tradeCounts[i] = expected[date.Format("2006-01-02")].Rows
+	}
+	if err := s.tradeRepo.RecordTradeLoads(ctx, filepath.Base(filePath), tradeDates, tradeCounts); err != nil {
+		logger.Error("Falha ao registrar a carga", err)
+	}
+
+	// As negociações já foram gravadas: uma falha na detecção é registrada sem falhar a
+	// ingestão e pode ser refeita com "ingest outliers detect".
+	if s.opts.DetectOutliers {
+		if _, err := s.detectOutliers(ctx, tradeDates, DefaultOutlierRules); err != nil {
+			logger.Error("Falha na detecção de outliers após a ingestão", err)
+		}
+	}
+
+	if s.opts.Verify {
+		verification, err := s.VerifyIngestion(ctx, filePath)
+		if err != nil {
+			return err
+		}
+		if !verification.Verified() {
+			return fmt.Errorf("service: reconciliação após a ingestão encontrou %d ticker(s) divergente(s) e %d linha(s) rejeitada(s); veja 'ingest verify -file %s'",
+				len(verification.Discrepancies), verification.Rejected, filePath)
+		}
+	}
+
+	return nil
+}
+
+// loadStaging executa os workers que gravam o arquivo na staging e retorna os
+// checksums por data (YYYY-MM-DD) e os resumos diários de tudo o que foi enviado ao banco.
+func (s *tradeServiceImpl) loadStaging(ctx context.Context, filePath, stagingTable string, progressTracker ProgressTracker) (map[string]entity.TradeChecksum, *ingestion.SummaryAccumulator, error) {
+	// Cancela a leitura do arquivo se algum worker falhar.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tradeCh, readErrCh := s.tradeReader.Read(ctx, filePath)
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, s.opts.Workers) // Canal para coletar erros dos workers
+	workerChecksums := make([]map[string]*entity.TradeChecksum, s.opts.Workers)
+	workerSummaries := make([]*ingestion.SummaryAccumulator, s.opts.Workers)
+
+	// Iniciar workers consumidores
+	for i := 0; i < s.opts.Workers; i++ {
+		checksums := make(map[string]*entity.TradeChecksum)
+		workerChecksums[i] = checksums
+		summaries := ingestion.NewSummaryAccumulator()
+		workerSummaries[i] = summaries
+
+		// Cada worker acumula seus próprios checksums e resumos, dispensando sincronização.
+		onRow := func(trade *entity.Trade) {
+			key := trade.TradeDate.Format("2006-01-02")
+			checksum, ok := checksums[key]
+			if !ok {
+				checksum = &entity.TradeChecksum{}
+				checksums[key] = checksum
+			}
+			checksum.Add(trade)
+			summaries.Add(trade)
+
+			// Increment progress tracker if available
+			if progressTracker != nil {
+				progressTracker.Increment()
+			}
+		}
+
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+			var err error
+			if s.opts.Mode == IngestionModeBatch {
+				err = s.saveInBatches(ctx, stagingTable, tradeCh, onRow)
+			} else {
+				_, err = s.tradeRepo.StreamTrades(ctx, stagingTable, tradeCh, s.opts.CommitInterval, onRow)
+			}
+			if err != nil {
+				errCh <- fmt.Errorf("worker %d: %w", workerID, err)
+				cancel()
+			}
+		}(i)
+	}
+
+	wg.Wait()    // Espera todos os workers terminarem
+	close(errCh) // Fecha o canal de erros após todos os workers terminarem
+
+	// Verifica se houve algum erro nos workers
+	for err := range errCh {
+		return nil, nil, err // Retorna o primeiro erro encontrado
+	}
+
+	// O leitor fecha o canal de erros antes do canal de negociações, então o
+	// resultado da leitura já está disponível aqui.
+	if err := <-readErrCh; err != nil {
+		return nil, nil, fmt.Errorf("service: leitura do arquivo interrompida: %w", err)
+	}
+
+	expected := make(map[string]entity.TradeChecksum)
+	for _, checksums := range workerChecksums {
+		for key, checksum := range checksums {
+			total := expected[key]
+			total.Merge(*checksum)
+			expected[key] = total
+		}
+	}
+
+	summaries := ingestion.NewSummaryAccumulator()
+	for _, workerSummary := range workerSummaries {
+		summaries.Merge(workerSummary)
+	}
+
+	return expected, summaries, nil
+}
+
+// saveInBatches acumula negociações em lotes de BatchSize e grava cada lote em
+// sua própria transação. Mantido como caminho de comparação para o modo "stream".
+func (s *tradeServiceImpl) saveInBatches(ctx context.Context, table string, tradeCh <-chan entity.Trade, onRow func(*entity.Trade)) error {
+	batch := make([]entity.Trade, 0, s.opts.BatchSize)
+	for trade := range tradeCh {
+		batch = append(batch, trade)
+		onRow(&batch[len(batch)-1])
+
+		if len(batch) >= s.opts.BatchSize {
+			// Salvar lote no banco de dados
+			if err := s.tradeRepo.SaveTrades(ctx, table, batch); err != nil {
+				return fmt.Errorf("falha ao salvar lote: %w", err) // Termina o worker em caso de erro fatal de DB
+			}
+			batch = make([]entity.Trade, 0, s.opts.BatchSize) // Reseta o lote
+		}
+	}
+	// Salvar qualquer lote restante no final do canal
+	if len(batch) > 0 {
+		if err := s.tradeRepo.SaveTrades(ctx, table, batch); err != nil {
+			return fmt.Errorf("falha ao salvar lote final: %w", err)
+		}
+	}
+	return nil
+}
+
+// compareChecksums verifica se cada data enviada pelo pipeline foi gravada na
+// staging com a mesma contagem de linhas, quantidade e soma de preços, e se a
+// staging não contém datas que o pipeline não enviou.
+func compareChecksums(expected, staged map[string]entity.TradeChecksum) error {
+	for key, want := range expected {
+		got, ok := staged[key]
+		if !ok {
+			return fmt.Errorf("data %s ausente na staging (%d linhas esperadas)", key, want.Rows)
+		}
+		if got != want {
+			return fmt.Errorf("divergência na data %s: esperado %+v, gravado %+v", key, want, got)
+		}
+	}
+	for key, got := range staged {
+		if _, ok := expected[key]; !ok {
+			return fmt.Errorf("data %s presente na staging sem ter sido lida do arquivo (%d linhas)", key, got.Rows)
+		}
+	}
+	return nil
+}
+
+// checksumDates converte as chaves YYYY-MM-DD dos checksums em datas ordenadas.
+func checksumDates(checksums map[string]entity.TradeChecksum) ([]time.Time, error) {
+	dates := make([]time.Time, 0, len(checksums))
+	for key := range checksums {
+		date, err := time.Parse("2006-01-02", key)
+		if err != nil {
+			return nil, fmt.Errorf("data inválida no checksum '%s': %w", key, err)
+		}
+		dates = append(dates, date)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates, nil
+}
+
+// RebuildDailySummaries recalcula, a partir de 'trades', os resumos diários das datas
+// no intervalo [from, to] (datas zeradas não limitam o intervalo). Com missingOnly,
+// apenas as datas carregadas antes da existência de daily_summaries são recalculadas.
+// Retorna as datas processadas.
+func (s *tradeServiceImpl) RebuildDailySummaries(ctx context.Context, from, to time.Time, missingOnly bool) ([]time.Time, error) {
+	dates, err := s.tradeRepo.ListTradeDates(ctx, from, to, missingOnly)
+	if err != nil {
+		return nil, fmt.Errorf("service: %w", err)
+	}
+
+	rebuilt := make([]time.Time, 0, len(dates))
+	for _, date := range dates {
+		count, err := s.tradeRepo.RebuildDailySummaries(ctx, date)
+		if err != nil {
+			return rebuilt, fmt.Errorf("service: falha ao recalcular resumos de %s: %w", date.Format("2006-01-02"), err)
+		}
+		logger.Info("Resumos diários recalculados",
+			zap.String("trade_date", date.Format("2006-01-02")),
+			zap.Int64("summaries", count))
+		rebuilt = append(rebuilt, date)
+	}
+
+	return rebuilt, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/config/logger"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/ingestion"
+	"go.uber.org/zap"
+)
+
+// VerifyIngestion relê filePath e compara, por data e ticker, o número de negociações, a
+// quantidade e a soma de preço x quantidade do arquivo com o que está gravado em 'trades'
+// para as datas do arquivo. As linhas de dados do arquivo também são contadas de forma
+// independente do reader: as que ele rejeitou ficam em Rejected. O resultado é
+// registrado em trade_loads: as datas sem divergências ficam marcadas como verificadas,
+// desde que nenhuma linha tenha sido rejeitada.
+func (s *tradeServiceImpl) VerifyIngestion(ctx context.Context, filePath string) (*entity.LoadVerification, error) {
+	if s.tradeReader == nil {
+		return nil, fmt.Errorf("service: trade reader not available - verification not supported in this context")
+	}
+
+	fileTotals, err := s.readLoadTotals(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(fileTotals) == 0 {
+		return nil, fmt.Errorf("service: nenhuma negociação lida de %s", filePath)
+	}
+	lines, err := ingestion.CountDataLines(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("service: %w", err)
+	}
+
+	dates := make([]time.Time, 0, len(fileTotals))
+	for key := range fileTotals {
+		date, err := time.Parse("2006-01-02", key)
+		if err != nil {
+			return nil, fmt.Errorf("service: data inválida no arquivo '%s': %w", key, err)
+		}
+		dates = append(dates, date)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	stored, err := s.tradeRepo.GetLoadTotals(ctx, dates)
+	if err != nil {
+		return nil, fmt.Errorf("service: %w", err)
+	}
+
+	verification := &entity.LoadVerification{
+		File:          filePath,
+		TradeDates:    dates,
+		Lines:         lines,
+		Discrepancies: []entity.LoadDiscrepancy{},
+	}
+	tradeCounts := make([]int64, len(dates))
+	discrepancies := make([]int32, len(dates))
+	for i, date := range dates {
+		key := date.Format("2006-01-02")
+		diffs, rows := compareLoadTotals(date, fileTotals[key], stored[key])
+		verification.Tickers += len(fileTotals[key])
+		verification.Rows += rows
+		verification.Discrepancies = append(verification.Discrepancies, diffs...)
+		tradeCounts[i] = rows
+		discrepancies[i] = int32(len(diffs))
+
+		logger.Info("Carga reconciliada",
+			zap.String("trade_date", key),
+			zap.Int64("rows", rows),
+			zap.Int("discrepancies", len(diffs)))
+	}
+
+	// Linhas rejeitadas não têm data confiável: o total do arquivo é registrado em todas
+	// as datas e impede que qualquer uma seja marcada como verificada.
+	verification.Rejected = lines - verification.Rows
+	if verification.Rejected < 0 {
+		return nil, fmt.Errorf("service: %s tem %d linha(s) de dados, mas %d negociações foram lidas; o arquivo foi alterado durante a reconciliação?",
+			filePath, lines, verification.Rows)
+	}
+	if verification.Rejected > 0 {
+		logger.Info("⚠️ Linhas rejeitadas na releitura do arquivo; veja errors.log",
+			zap.String("file", filePath),
+			zap.Int64("lines", lines),
+			zap.Int64("rejected", verification.Rejected))
+	}
+
+	if err := s.tradeRepo.RecordLoadVerification(ctx, filepath.Base(filePath), dates, tradeCounts, discrepancies, verification.Rejected); err != nil {
+		return nil, fmt.Errorf("service: %w", err)
+	}
+
+	return verification, nil
+}
+
+// readLoadTotals lê o arquivo por completo e acumula os totais por data (YYYY-MM-DD) e
+// ticker. Linhas que não podem ser interpretadas são ignoradas, como na ingestão.
+func (s *tradeServiceImpl) readLoadTotals(ctx context.Context, filePath string) (map[string]map[string]entity.LoadTotals, error) {
+	tradeCh, readErrCh := s.tradeReader.Read(ctx, filePath)
+
+	totals := make(map[string]map[string]entity.LoadTotals)
+	for trade := range tradeCh {
+		key := trade.TradeDate.Format("2006-01-02")
+		byTicker, ok := totals[key]
+		if !ok {
+			byTicker = make(map[string]entity.LoadTotals)
+			totals[key] = byTicker
+		}
+		t := byTicker[trade.InstrumentCode]
+		t.Add(&trade)
+		byTicker[trade.InstrumentCode] = t
+	}
+
+	if err := <-readErrCh; err != nil {
+		return nil, fmt.Errorf("service: leitura do arquivo interrompida: %w", err)
+	}
+	return totals, nil
+}
+
+// compareLoadTotals compara os totais de um pregão no arquivo e no banco e retorna as
+// divergências em ordem de ticker, junto com o número de negociações do arquivo.
+func compareLoadTotals(date time.Time, file, stored map[string]entity.LoadTotals) ([]entity.LoadDiscrepancy, int64) {
+	tickers := make([]string, 0, len(file))
+	var rows int64
+	for ticker, t := range file {
+		tickers = append(tickers, ticker)
+		rows += t.Rows
+	}
+	for ticker := range stored {
+		if _, ok := file[ticker]; !ok {
+			tickers = append(tickers, ticker)
+		}
+	}
+	sort.Strings(tickers)
+
+	var diffs []entity.LoadDiscrepancy
+	for _, ticker := range tickers {
+		if file[ticker] != stored[ticker] {
+			diffs = append(diffs, entity.LoadDiscrepancy{
+				TradeDate: date, InstrumentCode: ticker, File: file[ticker], Stored: stored[ticker],
+			})
+		}
+	}
+	return diffs, rows
+}
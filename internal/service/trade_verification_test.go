@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+// fakeTradeReader envia negociações fixas, como o reader faria com as linhas válidas.
+type fakeTradeReader struct {
+	trades []entity.Trade
+}
+
+func (f *fakeTradeReader) Read(ctx context.Context, path string) (<-chan entity.Trade, <-chan error) {
+	tradeCh := make(chan entity.Trade, len(f.trades))
+	errCh := make(chan error)
+	for _, trade := range f.trades {
+		tradeCh <- trade
+	}
+	close(errCh)
+	close(tradeCh)
+	return tradeCh, errCh
+}
+
+const verificationHeader = "DataReferencia;CodigoInstrumento;AcaoAtualizacao;PrecoNegocio;QuantidadeNegociada;HoraFechamento;CodigoIdentificadorNegocio;TipoSessaoPregao;DataNegocio;CodigoParticipanteComprador;CodigoParticipanteVendedor\n"
+
+// Totais de duas negociações de PETR4 a 30,00 (10 e 20 unidades) em 02/01/2024.
+var verificationTotals = map[string]map[string]entity.LoadTotals{
+	"2024-01-02": {"PETR4": {Rows: 2, Quantity: 30, NotionalTicks: 300000 * 30}},
+}
+
+func TestVerifyIngestion(t *testing.T) {
+	trades := []entity.Trade{
+		{TradeDate: testDate(2), InstrumentCode: "PETR4", NegotiatedPrice: 30, NegotiatedQuantity: 10},
+		{TradeDate: testDate(2), InstrumentCode: "PETR4", NegotiatedPrice: 30, NegotiatedQuantity: 20},
+	}
+	const valid = "2024-01-02;PETR4;0;30,00;10;100000000;10;1;2024-01-02;3;120\n" +
+		"2024-01-02;PETR4;0;30,00;20;100000000;20;1;2024-01-02;3;120\n"
+
+	tests := []struct {
+		name         string
+		content      string
+		stored       map[string]map[string]entity.LoadTotals
+		rejected     int64
+		tickerDiffs  int32
+		wantVerified bool
+	}{
+		{"arquivo conferido", verificationHeader + valid, verificationTotals, 0, 0, true},
+		// A linha sem colunas suficientes é descartada pelo reader: os totais por ticker
+		// conferem, mas a carga não pode ser dada como verificada.
+		{"linha rejeitada", verificationHeader + valid + "2024-01-02;PETR4;0;30,00\n", verificationTotals, 1, 0, false},
+		{"ticker divergente", verificationHeader + valid, map[string]map[string]entity.LoadTotals{}, 0, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "trades.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("gravar arquivo: %v", err)
+			}
+			repo := &fakeTradeRepository{totals: tt.stored}
+			svc := NewTradeService(&fakeTradeReader{trades: trades}, repo, nil, IngestionOptions{})
+
+			verification, err := svc.VerifyIngestion(context.Background(), path)
+			if err != nil {
+				t.Fatalf("VerifyIngestion: %v", err)
+			}
+			if verification.Lines != 2+tt.rejected || verification.Rows != 2 || verification.Rejected != tt.rejected {
+				t.Errorf("linhas/negociações/rejeitadas = %d/%d/%d, esperado %d/2/%d",
+					verification.Lines, verification.Rows, verification.Rejected, 2+tt.rejected, tt.rejected)
+			}
+			if verification.Verified() != tt.wantVerified {
+				t.Errorf("Verified() = %v, esperado %v", verification.Verified(), tt.wantVerified)
+			}
+			if repo.recordedRejected != tt.rejected || !reflect.DeepEqual(repo.recordedDiscrepancies, []int32{tt.tickerDiffs}) {
+				t.Errorf("registrado: rejeitadas %d e divergências %v, esperado %d e [%d]",
+					repo.recordedRejected, repo.recordedDiscrepancies, tt.rejected, tt.tickerDiffs)
+			}
+		})
+	}
+}
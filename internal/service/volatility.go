@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+)
+
+const (
+	// tradingDaysPerYear anualiza as volatilidades diárias.
+	tradingDaysPerYear = 252
+	// intradayBarMinutes é a duração das barras da volatilidade realizada intradiária.
+	intradayBarMinutes = 5
+
+	defaultVolatilityWindow = 20
+	maxVolatilityWindow     = 250
+)
+
+// RetrieveVolatility calcula a série de log-retornos diários do instrumento entre
+// 'from' e 'to' com as volatilidades realizadas close-to-close, de Parkinson (máxima e
+// mínima do dia) e intradiária (barras de intradayBarMinutes minutos), em janelas móveis
+// de 'window' pregões e no período inteiro, além do drawdown. As volatilidades são
+// anualizadas por tradingDaysPerYear pregões. As datas seguem a regra comum de
+// parseDateRange; sessionStr restringe a um tipo de sessão.
+func (s *tradeServiceImpl) RetrieveVolatility(ctx context.Context, instrumentCode, fromStr, toStr, windowStr, sessionStr string) (*entity.VolatilityReport, error) {
+	r, err := s.parseDateRange("from", fromStr, "to", toStr)
+	if err != nil {
+		return nil, err
+	}
+	session, err := parseSession(sessionStr)
+	if err != nil {
+		return nil, err
+	}
+
+	window := defaultVolatilityWindow
+	if windowStr != "" {
+		n, err := strconv.Atoi(windowStr)
+		if err != nil || n < 2 || n > maxVolatilityWindow {
+			return nil, invalidParameter("'window' deve ser um inteiro entre 2 e %d", maxVolatilityWindow)
+		}
+		window = n
+	}
+
+	days, err := s.tradeRepo.GetDailySeries(ctx, instrumentCode, r.From, r.To, session)
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao buscar série diária: %w", err)
+	}
+	variances, err := s.tradeRepo.GetIntradayVariance(ctx, repository.TradeQuery{InstrumentCode: instrumentCode, From: r.From, To: r.To, Session: session}, intradayBarMinutes)
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao calcular volatilidade intradiária: %w", err)
+	}
+	intraday := make(map[time.Time]float64, len(variances))
+	for _, v := range variances {
+		intraday[v.TradeDate] = v.Variance
+	}
+
+	report := &entity.VolatilityReport{
+		InstrumentCode: instrumentCode, From: r.From, To: r.To,
+		Window: window, BarMinutes: intradayBarMinutes, TradingDays: len(days),
+		Days: make([]entity.DailyReturn, len(days)),
+	}
+
+	// Séries diárias alinhadas a 'days': retornos (NaN no primeiro pregão ou sem preço),
+	// variâncias de Parkinson e intradiárias (NaN sem negociações nas barras).
+	returns := make([]float64, len(days))
+	parkinson := make([]float64, len(days))
+	realized := make([]float64, len(days))
+	peak, peakIndex := 0.0, 0
+	for i, d := range days {
+		returns[i] = math.NaN()
+		if i > 0 && days[i-1].Close > 0 && d.Close > 0 {
+			returns[i] = math.Log(d.Close / days[i-1].Close)
+		}
+		if d.Low > 0 {
+			parkinson[i] = math.Pow(math.Log(d.High/d.Low), 2) / (4 * math.Ln2)
+		}
+		realized[i] = math.NaN()
+		if v, ok := intraday[d.TradeDate]; ok {
+			realized[i] = v
+		}
+
+		day := entity.DailyReturn{
+			TradeDate: d.TradeDate,
+			Close:     d.Close,
+			LogReturn: optionalRound(returns[i]),
+			Parkinson: roundTo(annualize(parkinson[i]), 6),
+			Intraday:  optionalRound(annualize(realized[i])),
+		}
+		if i+1 >= window {
+			day.RollingParkinson = optionalRound(annualize(mean(parkinson[i+1-window : i+1])))
+			day.RollingIntraday = optionalRound(annualize(mean(realized[i+1-window : i+1])))
+		}
+		if i >= window {
+			day.RollingCloseToClose = optionalRound(annualize(sampleVariance(returns[i+1-window : i+1])))
+		}
+
+		if d.Close > peak {
+			peak, peakIndex = d.Close, i
+		}
+		if peak > 0 {
+			day.Drawdown = roundTo(d.Close/peak-1, 6)
+		}
+		if day.Drawdown < report.MaxDrawdown {
+			report.MaxDrawdown = day.Drawdown
+			report.PeakDate, report.TroughDate = &days[peakIndex].TradeDate, &days[i].TradeDate
+		}
+		if days[0].Close > 0 {
+			day.CumulativeReturn = roundTo(d.Close/days[0].Close-1, 6)
+		}
+		report.Days[i] = day
+	}
+
+	report.CumulativeReturn = report.Days[len(days)-1].CumulativeReturn
+	report.MeanLogReturn = optionalRound(mean(skipNaN(returns)))
+	report.CloseToClose = optionalRound(annualize(sampleVariance(skipNaN(returns))))
+	report.Parkinson = roundTo(annualize(mean(parkinson)), 6)
+	report.Intraday = optionalRound(annualize(mean(skipNaN(realized))))
+	return report, nil
+}
+
+// annualize converte uma variância diária em volatilidade anualizada.
+func annualize(variance float64) float64 {
+	return math.Sqrt(variance * tradingDaysPerYear)
+}
+
+// mean retorna a média dos valores; NaN se vazio ou se algum valor for NaN.
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// sampleVariance retorna a variância amostral (n - 1) dos valores; NaN com menos de
+// dois valores ou se algum valor for NaN.
+func sampleVariance(values []float64) float64 {
+	if len(values) < 2 {
+		return math.NaN()
+	}
+	m := mean(values)
+	var sum float64
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+	return sum / float64(len(values)-1)
+}
+
+// skipNaN retorna os valores que não são NaN.
+func skipNaN(values []float64) []float64 {
+	out := make([]float64, 0, len(values))
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// optionalRound arredonda v para 6 casas; NaN retorna nil (nulo no JSON).
+func optionalRound(v float64) *float64 {
+	if math.IsNaN(v) {
+		return nil
+	}
+	v = roundTo(v, 6)
+	return &v
+}
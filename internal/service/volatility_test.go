@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+)
+
+func testDate(day int) time.Time {
+	return time.Date(2024, time.January, day, 0, 0, 0, 0, time.UTC)
+}
+
+// ohlc cria um pregão de janeiro de 2024 com máxima, mínima e fechamento.
+func ohlc(day int, high, low, close float64) entity.DailyOHLCV {
+	return entity.DailyOHLCV{TradeDate: testDate(day), Open: close, High: high, Low: low, Close: close}
+}
+
+func retrieveVolatility(t *testing.T, repo *fakeTradeRepository, window string) *entity.VolatilityReport {
+	t.Helper()
+	svc := NewTradeService(nil, repo, nil, IngestionOptions{})
+	report, err := svc.RetrieveVolatility(context.Background(), "PETR4", "2024-01-02", "2024-01-05", window, "")
+	if err != nil {
+		t.Fatalf("RetrieveVolatility: %v", err)
+	}
+	return report
+}
+
+// assertClose compara valores arredondados a 6 casas.
+func assertClose(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-6 {
+		t.Errorf("%s = %.6f, esperado %.6f", name, got, want)
+	}
+}
+
+func assertOptional(t *testing.T, name string, got *float64, want float64) {
+	t.Helper()
+	if got == nil {
+		t.Errorf("%s = nulo, esperado %.6f", name, want)
+		return
+	}
+	assertClose(t, name, *got, want)
+}
+
+func assertNil(t *testing.T, name string, got *float64) {
+	t.Helper()
+	if got != nil {
+		t.Errorf("%s = %.6f, esperado nulo", name, *got)
+	}
+}
+
+// Fechamentos 100, 110, 99 e 108,9: log-retornos ln(1,1) = 0,095310, ln(0,9) = -0,105361
+// e ln(1,1) de novo. Parkinson diário = ln(H/L)² / (4 ln 2), anualizado por sqrt(252 * v).
+func TestRetrieveVolatilityKnownSeries(t *testing.T) {
+	repo := &fakeTradeRepository{
+		days: []entity.DailyOHLCV{
+			ohlc(2, 105, 95, 100),
+			ohlc(3, 121, 110, 110),
+			ohlc(4, 110, 99, 99),
+			ohlc(5, 110, 99, 108.9),
+		},
+		variances: []entity.IntradayVariance{
+			{TradeDate: testDate(4), Variance: 0.0001, Bars: 80},
+			{TradeDate: testDate(5), Variance: 0.0001, Bars: 80},
+		},
+	}
+	report := retrieveVolatility(t, repo, "2")
+
+	if report.TradingDays != 4 || len(report.Days) != 4 {
+		t.Fatalf("pregões = %d (%d dias), esperado 4", report.TradingDays, len(report.Days))
+	}
+
+	days := report.Days
+	assertNil(t, "log_return[0]", days[0].LogReturn)
+	for i, want := range []float64{0.095310, -0.105361, 0.095310} {
+		assertOptional(t, "log_return", days[i+1].LogReturn, want)
+	}
+	// ln(105/95)² / (4 ln 2) = 0,003613 → sqrt(252 * 0,003613) = 0,954157; os dois
+	// últimos pregões têm H/L = 110/99 = 1/0,9.
+	for i, want := range []float64{0.954157, 0.908650, 1.004466, 1.004466} {
+		assertClose(t, "parkinson", days[i].Parkinson, want)
+	}
+
+	// Janela 2: Parkinson a partir do 2º pregão; close-to-close a partir do 3º (dois
+	// retornos). Em ambas as janelas de retornos os valores são ±ln(1,1)/ln(0,9), com
+	// variância amostral (0,095310 + 0,105361)² / 2 = 0,020134 → 2,252523.
+	assertNil(t, "rolling_parkinson[0]", days[0].RollingParkinson)
+	for i, want := range []float64{0.931682, 0.957757, 1.004466} {
+		assertOptional(t, "rolling_parkinson", days[i+1].RollingParkinson, want)
+	}
+	assertNil(t, "rolling_close_to_close[1]", days[1].RollingCloseToClose)
+	assertOptional(t, "rolling_close_to_close[2]", days[2].RollingCloseToClose, 2.252523)
+	assertOptional(t, "rolling_close_to_close[3]", days[3].RollingCloseToClose, 2.252523)
+
+	// Variância intradiária 0,0001 → sqrt(252 * 0,0001) = 0,158745; sem barras no 1º e 2º
+	// pregões, a média móvel só existe quando a janela cobre os dois últimos.
+	assertNil(t, "intraday[0]", days[0].Intraday)
+	assertOptional(t, "intraday[2]", days[2].Intraday, 0.158745)
+	assertNil(t, "rolling_intraday[1]", days[1].RollingIntraday)
+	assertNil(t, "rolling_intraday[2]", days[2].RollingIntraday)
+	assertOptional(t, "rolling_intraday[3]", days[3].RollingIntraday, 0.158745)
+
+	// Pico em 110 (03/01) e vale em 99 (04/01): drawdown de -10%; 108,9 fica 1% abaixo.
+	for i, want := range []float64{0, 0, -0.1, -0.01} {
+		assertClose(t, "drawdown", days[i].Drawdown, want)
+	}
+	assertClose(t, "max_drawdown", report.MaxDrawdown, -0.1)
+	if report.PeakDate == nil || !report.PeakDate.Equal(testDate(3)) || report.TroughDate == nil || !report.TroughDate.Equal(testDate(4)) {
+		t.Errorf("pico/vale = %v/%v, esperado 2024-01-03/2024-01-04", report.PeakDate, report.TroughDate)
+	}
+
+	// Período: média (2 ln 1,1 + ln 0,9) / 3 = 0,028420; variância amostral dos três
+	// retornos 0,013423 → 1,839177; Parkinson pela média das quatro variâncias diárias.
+	assertOptional(t, "mean_log_return", report.MeanLogReturn, 0.028420)
+	assertOptional(t, "close_to_close", report.CloseToClose, 1.839177)
+	assertClose(t, "parkinson", report.Parkinson, 0.968758)
+	assertOptional(t, "intraday", report.Intraday, 0.158745)
+	assertClose(t, "cumulative_return", report.CumulativeReturn, 0.089)
+}
+
+// Com um único pregão não há retornos: as medidas de retorno são nulas e apenas a
+// volatilidade de Parkinson do próprio dia é calculada.
+func TestRetrieveVolatilitySingleDay(t *testing.T) {
+	repo := &fakeTradeRepository{days: []entity.DailyOHLCV{ohlc(2, 105, 95, 100)}}
+	report := retrieveVolatility(t, repo, "")
+
+	if report.Window != defaultVolatilityWindow || report.TradingDays != 1 {
+		t.Fatalf("janela/pregões = %d/%d, esperado %d/1", report.Window, report.TradingDays, defaultVolatilityWindow)
+	}
+	day := report.Days[0]
+	assertNil(t, "log_return", day.LogReturn)
+	assertNil(t, "intraday", day.Intraday)
+	assertNil(t, "rolling_close_to_close", day.RollingCloseToClose)
+	assertNil(t, "rolling_parkinson", day.RollingParkinson)
+	assertClose(t, "parkinson[0]", day.Parkinson, 0.954157)
+
+	assertNil(t, "mean_log_return", report.MeanLogReturn)
+	assertNil(t, "close_to_close", report.CloseToClose)
+	assertNil(t, "intraday", report.Intraday)
+	assertClose(t, "parkinson", report.Parkinson, 0.954157)
+	assertClose(t, "cumulative_return", report.CumulativeReturn, 0)
+	assertClose(t, "max_drawdown", report.MaxDrawdown, 0)
+	if report.PeakDate != nil || report.TroughDate != nil {
+		t.Errorf("pico/vale = %v/%v, esperado nulos", report.PeakDate, report.TroughDate)
+	}
+}
+
+// Preço constante: retornos nulos (zero), desvio padrão zero em todas as medidas e
+// nenhum drawdown.
+func TestRetrieveVolatilityFlatPrices(t *testing.T) {
+	repo := &fakeTradeRepository{}
+	for day := 2; day <= 5; day++ {
+		repo.days = append(repo.days, ohlc(day, 50, 50, 50))
+	}
+	report := retrieveVolatility(t, repo, "2")
+
+	for i, day := range report.Days {
+		if i > 0 {
+			assertOptional(t, "log_return", day.LogReturn, 0)
+			assertOptional(t, "rolling_parkinson", day.RollingParkinson, 0)
+		}
+		if i > 1 {
+			assertOptional(t, "rolling_close_to_close", day.RollingCloseToClose, 0)
+		}
+		assertClose(t, "parkinson", day.Parkinson, 0)
+		assertClose(t, "drawdown", day.Drawdown, 0)
+	}
+	assertOptional(t, "mean_log_return", report.MeanLogReturn, 0)
+	assertOptional(t, "close_to_close", report.CloseToClose, 0)
+	assertClose(t, "parkinson", report.Parkinson, 0)
+	assertClose(t, "max_drawdown", report.MaxDrawdown, 0)
+	assertClose(t, "cumulative_return", report.CumulativeReturn, 0)
+}
+
+func TestSampleVariance(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"vazio", nil, math.NaN()},
+		{"um valor", []float64{1}, math.NaN()},
+		{"constante", []float64{3, 3, 3}, 0},
+		{"dois valores", []float64{1, 3}, 2},
+		{"quatro valores", []float64{2, 4, 4, 6}, 8.0 / 3},
+		{"com NaN", []float64{1, math.NaN(), 3}, math.NaN()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sampleVariance(tt.values)
+			if math.IsNaN(tt.want) {
+				if !math.IsNaN(got) {
+					t.Errorf("sampleVariance(%v) = %v, esperado NaN", tt.values, got)
+				}
+				return
+			}
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("sampleVariance(%v) = %v, esperado %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
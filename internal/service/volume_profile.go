@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/entity"
+	"github.com/CharlesTenorioDev/b3-trade-aggregator/internal/repository"
+)
+
+// Tipos de faixa do perfil de volume.
+const (
+	BucketTypeTick    = "tick"    // Faixas de N ticks de preço
+	BucketTypePercent = "percent" // Faixas de N% do menor preço do período
+)
+
+const (
+	maxProfileTicks       = 10000
+	maxProfilePercent     = 50
+	defaultValueAreaShare = 70
+)
+
+// VolumeProfileRequest reúne os parâmetros de RetrieveVolumeProfile como recebidos na
+// API. 'Date' consulta um único pregão; 'From'/'To' um intervalo. Sem nenhum deles,
+// usa o pregão anterior a hoje.
+type VolumeProfileRequest struct {
+	Date       string
+	From, To   string
+	BucketType string // tick (padrão) ou percent
+	BucketSize string // Ticks (inteiro, padrão 1) ou % do menor preço
+	ValueArea  string // % do volume na área de valor (padrão 70)
+	TickSize   string // Variação mínima de preço; vazio usa a do instrumento (entity.PriceTickOf)
+	Session    string // Tipo de sessão; vazio considera todas
+}
+
+// profileBucket acumula as negociações de uma faixa do perfil, em unidades de entity.PriceScale.
+type profileBucket struct {
+	index           int64
+	minPrice        int64
+	maxPrice        int64
+	volume          int64
+	financialVolume float64
+	tradeCount      int64
+}
+
+// RetrieveVolumeProfile calcula o perfil de volume do instrumento: a quantidade
+// negociada em cada faixa de preço, o ponto de controle (POC) e a área de valor.
+// As faixas têm largura de N ticks ou de N% do menor preço do período (arredondada
+// para ticks inteiros, no mínimo um). O tick é o informado em TickSize ou, sem ele, o
+// do instrumento. A área de valor parte do POC e incorpora, a cada
+// passo, a faixa vizinha (acima ou abaixo) de maior volume até somar o percentual pedido.
+func (s *tradeServiceImpl) RetrieveVolumeProfile(ctx context.Context, instrumentCode string, req VolumeProfileRequest) (*entity.VolumeProfile, error) {
+	bucketType := req.BucketType
+	if bucketType == "" {
+		bucketType = BucketTypeTick
+	}
+	bucketSize := 1.0
+	switch bucketType {
+	case BucketTypeTick:
+		if req.BucketSize != "" {
+			n, err := strconv.Atoi(req.BucketSize)
+			if err != nil || n < 1 || n > maxProfileTicks {
+				return nil, invalidParameter("'bucket_size' deve ser um inteiro entre 1 e %d para faixas em ticks", maxProfileTicks)
+			}
+			bucketSize = float64(n)
+		}
+	case BucketTypePercent:
+		if req.BucketSize == "" {
+			return nil, invalidParameter("'bucket_size' é obrigatório para faixas em percentual")
+		}
+		pct, err := strconv.ParseFloat(req.BucketSize, 64)
+		if err != nil || pct <= 0 || pct > maxProfilePercent {
+			return nil, invalidParameter("'bucket_size' deve ser um percentual maior que 0 e até %d", maxProfilePercent)
+		}
+		bucketSize = pct
+	default:
+		return nil, invalidParameter("'bucket_type' inválido ('%s'). Use tick ou percent", req.BucketType)
+	}
+
+	valueArea := float64(defaultValueAreaShare)
+	if req.ValueArea != "" {
+		pct, err := strconv.ParseFloat(req.ValueArea, 64)
+		if err != nil || pct <= 0 || pct > 100 {
+			return nil, invalidParameter("'value_area' deve ser um percentual maior que 0 e até 100")
+		}
+		valueArea = pct
+	}
+
+	tickSize := entity.PriceTickOf(instrumentCode)
+	if req.TickSize != "" {
+		value, err := strconv.ParseFloat(req.TickSize, 64)
+		if err != nil || value <= 0 || math.Round(value*entity.PriceScale) < 1 {
+			return nil, invalidParameter("'tick_size' deve ser um preço positivo de no mínimo %g", 1.0/entity.PriceScale)
+		}
+		tickSize = value
+	}
+	tick := int64(math.Round(tickSize * entity.PriceScale))
+
+	session, err := parseSession(req.Session)
+	if err != nil {
+		return nil, err
+	}
+	r, err := s.parseDayOrRange(req.Date, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := s.tradeRepo.GetPriceLevels(ctx, repository.TradeQuery{InstrumentCode: instrumentCode, From: r.From, To: r.To, Session: session})
+	if err != nil {
+		return nil, fmt.Errorf("service: falha ao calcular perfil de volume: %w", err)
+	}
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("service: dados não encontrados para %s entre %s e %s",
+			instrumentCode, r.From.Format("2006-01-02"), r.To.Format("2006-01-02"))
+	}
+
+	// Os níveis chegam em ordem de preço, então o primeiro define a base das faixas
+	// percentuais e as faixas são criadas já ordenadas.
+	width := int64(bucketSize) * tick
+	if bucketType == BucketTypePercent {
+		lowest := math.Round(prices[0].Price * entity.PriceScale)
+		width = max(int64(math.Round(math.Abs(lowest)*bucketSize/100/float64(tick))), 1) * tick
+	}
+
+	var buckets []*profileBucket
+	var volume, tradeCount int64
+	for _, p := range prices {
+		price := int64(math.Round(p.Price * entity.PriceScale))
+		index := floorDiv(price, width)
+		if len(buckets) == 0 || buckets[len(buckets)-1].index != index {
+			buckets = append(buckets, &profileBucket{index: index, minPrice: price})
+		}
+		b := buckets[len(buckets)-1]
+		b.maxPrice = price
+		b.volume += p.Volume
+		b.financialVolume += p.FinancialVolume
+		b.tradeCount += p.TradeCount
+		volume += p.Volume
+		tradeCount += p.TradeCount
+	}
+
+	poc := 0
+	for i, b := range buckets {
+		if b.volume > buckets[poc].volume {
+			poc = i
+		}
+	}
+	low, high := valueAreaBounds(buckets, poc, float64(volume)*valueArea/100)
+
+	profile := &entity.VolumeProfile{
+		InstrumentCode: instrumentCode, From: r.From, To: r.To,
+		BucketType: bucketType, BucketSize: bucketSize, BucketWidth: float64(width) / entity.PriceScale,
+		TickSize: float64(tick) / entity.PriceScale, Volume: volume, TradeCount: tradeCount, ValueAreaPct: valueArea,
+		ValueAreaLow:  float64(buckets[low].minPrice) / entity.PriceScale,
+		ValueAreaHigh: float64(buckets[high].maxPrice) / entity.PriceScale,
+		Levels:        make([]entity.VolumeProfileLevel, len(buckets)),
+	}
+	if b := buckets[poc]; b.volume > 0 {
+		profile.POC = roundTo(b.financialVolume/float64(b.volume), 6)
+	}
+	for i, b := range buckets {
+		profile.Levels[i] = entity.VolumeProfileLevel{
+			PriceLow:        float64(b.index*width) / entity.PriceScale,
+			PriceHigh:       float64((b.index+1)*width) / entity.PriceScale,
+			Volume:          b.volume,
+			FinancialVolume: roundTo(b.financialVolume, 4),
+			TradeCount:      b.tradeCount,
+			VolumeShare:     roundTo(share(b.volume, volume), 4),
+			InValueArea:     i >= low && i <= high,
+		}
+	}
+	return profile, nil
+}
+
+// valueAreaBounds expande a área de valor a partir da faixa poc, incorporando a cada
+// passo a vizinha de maior volume (a de cima em caso de empate), até o volume da área
+// atingir target. Retorna os índices da primeira e da última faixa da área.
+func valueAreaBounds(buckets []*profileBucket, poc int, target float64) (low, high int) {
+	low, high = poc, poc
+	covered := float64(buckets[poc].volume)
+	for covered < target && (low > 0 || high < len(buckets)-1) {
+		var below, above int64 = -1, -1
+		if low > 0 {
+			below = buckets[low-1].volume
+		}
+		if high < len(buckets)-1 {
+			above = buckets[high+1].volume
+		}
+		if above >= below {
+			high++
+			covered += float64(above)
+		} else {
+			low--
+			covered += float64(below)
+		}
+	}
+	return low, high
+}
+
+// floorDiv divide arredondando para baixo, também para preços negativos.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,4 @@
+-- migrations/001_initial_schema.down.sql
+
+-- Remove a tabela 'trades' e todos os seus dados.
+DROP TABLE IF EXISTS trades;
@@ -1,4 +1,4 @@
--- migrations/001_create_trades_table.up.sql
+-- migrations/001_initial_schema.up.sql
 
 -- Cria a tabela 'trades' para armazenar as negociações
 CREATE TABLE IF NOT EXISTS trades (
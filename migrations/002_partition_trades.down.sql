@@ -0,0 +1,28 @@
+-- migrations/002_partition_trades.down.sql
+
+-- Reverte 'trades' para uma tabela heap única, copiando os dados de todas as partições.
+
+CREATE TABLE trades_unpartitioned (
+    id BIGSERIAL PRIMARY KEY,
+    trade_date DATE NOT NULL,
+    instrument_code VARCHAR(20) NOT NULL,
+    negotiated_price NUMERIC(18, 4) NOT NULL,
+    negotiated_quantity INTEGER NOT NULL,
+    closing_time VARCHAR(9) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+INSERT INTO trades_unpartitioned (id, trade_date, instrument_code, negotiated_price, negotiated_quantity, closing_time, created_at)
+SELECT id, trade_date, instrument_code, negotiated_price, negotiated_quantity, closing_time, created_at
+FROM trades;
+
+SELECT setval('trades_unpartitioned_id_seq', COALESCE((SELECT MAX(id) FROM trades_unpartitioned), 0) + 1, false);
+
+-- Remover a tabela pai remove também todas as partições e o índice BRIN.
+DROP TABLE trades;
+DROP FUNCTION IF EXISTS ensure_trades_partition(DATE);
+
+ALTER TABLE trades_unpartitioned RENAME TO trades;
+ALTER TABLE trades RENAME CONSTRAINT trades_unpartitioned_pkey TO trades_pkey;
+ALTER SEQUENCE trades_unpartitioned_id_seq RENAME TO trades_id_seq;
+CREATE INDEX IF NOT EXISTS idx_trades_instrument_date ON trades (instrument_code, trade_date);
@@ -0,0 +1,73 @@
+-- migrations/002_partition_trades.up.sql
+
+-- Converte a tabela 'trades' em uma tabela particionada por intervalo (RANGE) de trade_date,
+-- com uma partição por mês (trades_pYYYYMM). Os dados existentes são copiados para as novas
+-- partições e a tabela antiga é removida. O script é idempotente: em um banco já particionado
+-- nenhuma etapa é reexecutada. O runner de migrações executa o script em uma única transação.
+
+-- Renomeia a tabela heap original (se ainda não particionada) para liberar os nomes.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_class WHERE relname = 'trades' AND relkind = 'r') THEN
+        ALTER TABLE trades RENAME TO trades_unpartitioned;
+        ALTER TABLE trades_unpartitioned RENAME CONSTRAINT trades_pkey TO trades_unpartitioned_pkey;
+        ALTER INDEX idx_trades_instrument_date RENAME TO idx_trades_unpartitioned_instrument_date;
+        ALTER SEQUENCE trades_id_seq RENAME TO trades_unpartitioned_id_seq;
+    END IF;
+END $$;
+
+-- Tabela particionada. A chave primária precisa incluir a chave de particionamento.
+CREATE TABLE IF NOT EXISTS trades (
+    id BIGSERIAL,                                      -- ID único da negociação
+    trade_date DATE NOT NULL,                          -- Data em que a negociação ocorreu (chave de partição)
+    instrument_code VARCHAR(20) NOT NULL,              -- Código do instrumento (ticker), ex: PETR4
+    negotiated_price NUMERIC(18, 4) NOT NULL,          -- Valor unitário do ativo com precisão financeira
+    negotiated_quantity INTEGER NOT NULL,              -- Quantidade de ativos negociados
+    closing_time VARCHAR(9) NOT NULL,                  -- Horário da negociação no formato HHMMSSmmm
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(), -- Timestamp da criação do registro
+    PRIMARY KEY (id, trade_date)
+) PARTITION BY RANGE (trade_date);
+
+-- Índices declarados na tabela pai são criados localmente em cada partição, inclusive nas futuras.
+-- O B-tree atende às consultas por ticker e data; o BRIN é minúsculo e eficiente porque os dados
+-- são carregados em ordem de data, permitindo varreduras por intervalo dentro de cada mês.
+CREATE INDEX IF NOT EXISTS idx_trades_instrument_date ON trades (instrument_code, trade_date);
+CREATE INDEX IF NOT EXISTS idx_trades_trade_date_brin ON trades USING BRIN (trade_date);
+
+-- Cria (se necessário) a partição mensal que contém a data informada e retorna seu nome.
+-- Chamada pela ingestão antes de gravar cada data em 'trades'.
+CREATE OR REPLACE FUNCTION ensure_trades_partition(p_date DATE) RETURNS TEXT AS $$
+DECLARE
+    v_start DATE := date_trunc('month', p_date)::DATE;
+    v_end   DATE := (date_trunc('month', p_date) + INTERVAL '1 month')::DATE;
+    v_name  TEXT := format('trades_p%s', to_char(p_date, 'YYYYMM'));
+BEGIN
+    IF to_regclass(v_name) IS NULL THEN
+        EXECUTE format(
+            'CREATE TABLE IF NOT EXISTS %I PARTITION OF trades FOR VALUES FROM (%L) TO (%L)',
+            v_name, v_start, v_end
+        );
+    END IF;
+    RETURN v_name;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Copia os dados da tabela antiga para as partições e remove a tabela antiga.
+DO $$
+DECLARE
+    v_month DATE;
+BEGIN
+    IF to_regclass('trades_unpartitioned') IS NOT NULL THEN
+        FOR v_month IN SELECT DISTINCT date_trunc('month', trade_date)::DATE FROM trades_unpartitioned LOOP
+            PERFORM ensure_trades_partition(v_month);
+        END LOOP;
+
+        INSERT INTO trades (id, trade_date, instrument_code, negotiated_price, negotiated_quantity, closing_time, created_at)
+        SELECT id, trade_date, instrument_code, negotiated_price, negotiated_quantity, closing_time, created_at
+        FROM trades_unpartitioned;
+
+        PERFORM setval('trades_id_seq', COALESCE((SELECT MAX(id) FROM trades), 0) + 1, false);
+
+        DROP TABLE trades_unpartitioned;
+    END IF;
+END $$;
@@ -0,0 +1,3 @@
+-- migrations/003_daily_summaries.down.sql
+
+DROP TABLE IF EXISTS daily_summaries;
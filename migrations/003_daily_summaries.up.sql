@@ -0,0 +1,22 @@
+-- migrations/003_daily_summaries.up.sql
+
+-- Resumo diário por instrumento, calculado durante a ingestão e gravado na mesma
+-- transação que substitui as negociações da data. Abertura e fechamento seguem a
+-- ordem (closing_time, negotiated_price) das negociações do dia.
+CREATE TABLE IF NOT EXISTS daily_summaries (
+    trade_date DATE NOT NULL,                          -- Data do pregão
+    instrument_code VARCHAR(20) NOT NULL,              -- Código do instrumento (ticker), ex: PETR4
+    open_price NUMERIC(18, 4) NOT NULL,                -- Preço da primeira negociação do dia
+    high_price NUMERIC(18, 4) NOT NULL,                -- Maior preço do dia
+    low_price NUMERIC(18, 4) NOT NULL,                 -- Menor preço do dia
+    close_price NUMERIC(18, 4) NOT NULL,               -- Preço da última negociação do dia
+    volume BIGINT NOT NULL,                            -- Soma das quantidades negociadas
+    financial_volume NUMERIC(24, 4) NOT NULL,          -- Soma de preço * quantidade
+    trade_count BIGINT NOT NULL,                       -- Número de negociações
+    vwap NUMERIC(18, 6) NOT NULL,                      -- Preço médio ponderado por volume
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(), -- Momento do cálculo
+    PRIMARY KEY (instrument_code, trade_date)
+);
+
+-- Consultas de mercado (todas as ações de um dia) partem da data.
+CREATE INDEX IF NOT EXISTS idx_daily_summaries_trade_date ON daily_summaries (trade_date);
@@ -0,0 +1,5 @@
+-- migrations/004_trades_covering_index.down.sql
+
+CREATE INDEX IF NOT EXISTS idx_trades_instrument_date ON trades (instrument_code, trade_date);
+
+DROP INDEX IF EXISTS idx_trades_instrument_date_covering;
@@ -0,0 +1,10 @@
+-- migrations/004_trades_covering_index.up.sql
+
+-- Índice de cobertura para as consultas agregadas por ticker e período: além da chave
+-- (instrument_code, trade_date), inclui preço e quantidade nas folhas, permitindo que
+-- MAX(preço) e SUM(quantidade) por dia sejam resolvidos com index-only scan, sem visitar
+-- o heap. Substitui o índice composto original, que tem o mesmo prefixo de chave.
+CREATE INDEX IF NOT EXISTS idx_trades_instrument_date_covering
+    ON trades (instrument_code, trade_date) INCLUDE (negotiated_price, negotiated_quantity);
+
+DROP INDEX IF EXISTS idx_trades_instrument_date;
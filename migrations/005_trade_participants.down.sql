@@ -0,0 +1,6 @@
+-- migrations/005_trade_participants.down.sql
+
+DROP TABLE IF EXISTS brokers;
+
+ALTER TABLE trades DROP COLUMN IF EXISTS seller_participant;
+ALTER TABLE trades DROP COLUMN IF EXISTS buyer_participant;
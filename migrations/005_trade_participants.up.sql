@@ -0,0 +1,16 @@
+-- migrations/005_trade_participants.up.sql
+
+-- Participantes (corretoras) comprador e vendedor de cada negociação, como enviados pela
+-- B3 em CodigoParticipanteComprador e CodigoParticipanteVendedor. 0 indica participante
+-- não informado (ex: negociações carregadas antes desta versão). Com DEFAULT constante a
+-- coluna é adicionada sem reescrever as partições existentes.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS buyer_participant INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS seller_participant INTEGER NOT NULL DEFAULT 0;
+
+-- Mapeamento opcional do código de participante para o nome da corretora, carregado
+-- com "ingest brokers load". Códigos sem nome aparecem apenas pelo número na API.
+CREATE TABLE IF NOT EXISTS brokers (
+    participant_code INTEGER PRIMARY KEY,              -- Código do participante na B3
+    name VARCHAR(120) NOT NULL,                        -- Nome da corretora
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()  -- Momento da última carga
+);
@@ -0,0 +1,3 @@
+-- migrations/006_trade_session_type.down.sql
+
+ALTER TABLE trades DROP COLUMN IF EXISTS session_type;
@@ -0,0 +1,6 @@
+-- migrations/006_trade_session_type.up.sql
+
+-- Tipo de sessão do pregão de cada negociação (TipoSessaoPregao no arquivo da B3):
+-- 1 para o pregão regular e 6 para o after-market. 0 indica sessão não informada
+-- (ex: negociações carregadas antes desta versão).
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS session_type SMALLINT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- migrations/007_trade_outliers.down.sql
+
+DROP TABLE IF EXISTS trade_outliers;
@@ -0,0 +1,26 @@
+-- migrations/007_trade_outliers.up.sql
+
+-- Negociações marcadas pela detecção de outliers ("ingest outliers detect" ou ao final da
+-- ingestão). Cada execução substitui as marcações das datas processadas.
+--   block_quantity:  quantidade >= múltiplo do tamanho médio dos negócios do instrumento
+--   block_value:     volume financeiro >= múltiplo do valor médio dos negócios
+--   price_deviation: preço distante da média móvel dos negócios anteriores da mesma sessão
+--                    (em % ou desvios padrão)
+CREATE TABLE IF NOT EXISTS trade_outliers (
+    id BIGSERIAL PRIMARY KEY,                          -- ID da marcação
+    trade_date DATE NOT NULL,                          -- Data do pregão
+    instrument_code VARCHAR(20) NOT NULL,              -- Código do instrumento (ticker), ex: PETR4
+    closing_time VARCHAR(9) NOT NULL,                  -- Horário da negociação no formato HHMMSSmmm
+    negotiated_price NUMERIC(18, 4) NOT NULL,          -- Preço da negociação
+    negotiated_quantity INTEGER NOT NULL,              -- Quantidade negociada
+    buyer_participant INTEGER NOT NULL DEFAULT 0,      -- Participante comprador
+    seller_participant INTEGER NOT NULL DEFAULT 0,     -- Participante vendedor
+    session_type SMALLINT NOT NULL DEFAULT 0,          -- Tipo de sessão do pregão (0 se não informado)
+    kind VARCHAR(20) NOT NULL,                         -- block_quantity, block_value ou price_deviation
+    score NUMERIC(18, 4) NOT NULL,                     -- Múltiplo do tamanho típico ou desvio da média (%)
+    reference NUMERIC(24, 4) NOT NULL,                 -- Tamanho típico (ações ou R$) ou média móvel do preço
+    sigma NUMERIC(18, 4),                              -- Desvio em desvios padrão (price_deviation)
+    detected_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() -- Momento da detecção
+);
+
+CREATE INDEX IF NOT EXISTS idx_trade_outliers_date_instrument ON trade_outliers (trade_date, instrument_code);
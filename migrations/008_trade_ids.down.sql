@@ -0,0 +1,4 @@
+-- migrations/008_trade_ids.down.sql
+
+DROP TABLE IF EXISTS trade_id_issues;
+ALTER TABLE trades DROP COLUMN IF EXISTS trade_id;
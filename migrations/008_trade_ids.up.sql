@@ -0,0 +1,25 @@
+-- migrations/008_trade_ids.up.sql
+
+-- Identificador do negócio (CodigoIdentificadorNegocio no arquivo da B3), crescente por
+-- instrumento dentro do pregão. 0 indica identificador não informado (ex: negociações
+-- carregadas antes desta versão), ignorado pela verificação de completude.
+ALTER TABLE trades ADD COLUMN IF NOT EXISTS trade_id BIGINT NOT NULL DEFAULT 0;
+
+-- Falhas na sequência de identificadores encontradas pela verificação de completude
+-- (ao final da ingestão ou em "ingest gaps check"). Cada execução substitui os registros
+-- das datas verificadas.
+--   gap:       identificadores ausentes entre first_id e last_id (count negócios)
+--   duplicate: identificador first_id (= last_id) repetido em count linhas extras
+--   misaligned: count identificadores entre first_id e last_id fora do incremento da sequência
+CREATE TABLE IF NOT EXISTS trade_id_issues (
+    id BIGSERIAL PRIMARY KEY,                         -- ID do registro
+    trade_date DATE NOT NULL,                         -- Data do pregão
+    instrument_code VARCHAR(20) NOT NULL,             -- Código do instrumento (ticker), ex: PETR4
+    kind VARCHAR(20) NOT NULL,                        -- gap, duplicate ou misaligned
+    first_id BIGINT NOT NULL,                         -- Primeiro identificador afetado
+    last_id BIGINT NOT NULL,                          -- Último identificador afetado
+    count BIGINT NOT NULL,                            -- Negócios ausentes ou linhas repetidas
+    checked_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() -- Momento da verificação
+);
+
+CREATE INDEX IF NOT EXISTS idx_trade_id_issues_date_instrument ON trade_id_issues (trade_date, instrument_code);
@@ -0,0 +1,3 @@
+-- migrations/009_trade_loads.down.sql
+
+DROP TABLE IF EXISTS trade_loads;
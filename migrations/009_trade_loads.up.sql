@@ -0,0 +1,19 @@
+-- migrations/009_trade_loads.up.sql
+
+-- Última carga e última reconciliação de cada data de pregão. A ingestão grava a carga
+-- (loaded_at) e a reconciliação ("ingest verify" ou '-verify' na ingestão) relê o arquivo
+-- e compara, por ticker, linhas, quantidade e preço x quantidade com 'trades'.
+-- verified_at só é preenchido quando a reconciliação não encontra divergências nem
+-- linhas rejeitadas pelo reader. A carga e a reconciliação guardam cada uma o seu
+-- arquivo: reconciliar outro arquivo não altera a procedência da carga.
+CREATE TABLE IF NOT EXISTS trade_loads (
+    trade_date DATE PRIMARY KEY,                 -- Data do pregão
+    file_name TEXT,                              -- Arquivo da última carga (NULL se carregada antes desta versão)
+    trade_count BIGINT,                          -- Negociações da data no arquivo da carga
+    loaded_at TIMESTAMP WITH TIME ZONE,          -- Momento da última carga (NULL se carregada antes desta versão)
+    verified_file TEXT,                          -- Arquivo da última reconciliação
+    verified_count BIGINT,                       -- Negociações da data no arquivo reconciliado
+    verified_at TIMESTAMP WITH TIME ZONE,        -- Momento da última reconciliação sem divergências
+    discrepancies INTEGER,                       -- Tickers divergentes na última reconciliação (NULL se nunca verificada)
+    rejected_lines BIGINT                        -- Linhas do arquivo rejeitadas na última reconciliação
+);
@@ -0,0 +1,12 @@
+// Package migrations embute nos binários os scripts SQL versionados do banco de dados.
+//
+// Cada versão é composta por NNN_descricao.up.sql e NNN_descricao.down.sql e é
+// aplicada pelo runner em internal/migrate.
+package migrations
+
+import "embed"
+
+// FS contém todos os scripts de migração deste diretório.
+//
+//go:embed *.sql
+var FS embed.FS
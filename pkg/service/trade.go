@@ -36,7 +36,7 @@ func (s *tradeServiceImpl) ProcessIngestion(ctx context.Context, filePath string
 		batchSize  = 1000 // Tamanho do lote para inserção no banco de dados
 	)
 
-	tradeCh := s.tradeReader.Read(ctx, filePath) // Inicia a leitura do arquivo
+	tradeCh, _ := s.tradeReader.Read(ctx, filePath) // Inicia a leitura do arquivo
 
 	var wg sync.WaitGroup
 	errCh := make(chan error, numWorkers) // Canal para coletar erros dos workers
@@ -51,7 +51,7 @@ func (s *tradeServiceImpl) ProcessIngestion(ctx context.Context, filePath string
 				batch = append(batch, trade)
 				if len(batch) >= batchSize {
 					// Salvar lote no banco de dados
-					err := s.tradeRepo.SaveTrades(ctx, batch)
+					err := s.tradeRepo.SaveTrades(ctx, repository.TradesTable, batch)
 					if err != nil {
 						errCh <- fmt.Errorf("worker %d: falha ao salvar lote: %w", workerID, err)
 						return // Termina o worker em caso de erro fatal de DB
@@ -61,7 +61,7 @@ func (s *tradeServiceImpl) ProcessIngestion(ctx context.Context, filePath string
 			}
 			// Salvar qualquer lote restante no final do canal
 			if len(batch) > 0 {
-				err := s.tradeRepo.SaveTrades(ctx, batch)
+				err := s.tradeRepo.SaveTrades(ctx, repository.TradesTable, batch)
 				if err != nil {
 					errCh <- fmt.Errorf("worker %d: falha ao salvar lote final: %w", workerID, err)
 				}
@@ -98,7 +98,7 @@ func (s *tradeServiceImpl) RetrieveAggregatedData(ctx context.Context, instrumen
 		startDate = time.Now().AddDate(0, 0, -8) // Ajuste conforme a regra exata de "dias úteis"
 	}
 
-	data, err := s.tradeRepo.GetAggregatedData(ctx, instrumentCode, startDate)
+	data, err := s.tradeRepo.GetAggregatedData(ctx, instrumentCode, startDate, time.Now(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("service: falha ao buscar dados agregados: %w", err)
 	}
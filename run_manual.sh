@@ -145,7 +145,7 @@ build_applications() {
     
     # Build web application
     print_status "Building web application..."
-    go build -o bin/app cmd/app/main.go
+    go build -o bin/app ./cmd/app
     if [ $? -eq 0 ]; then
         print_success "Web application built successfully"
     else
@@ -155,7 +155,7 @@ build_applications() {
     
     # Build CLI application
     print_status "Building CLI application..."
-    go build -o bin/ingest cmd/ingest/main.go
+    go build -o bin/ingest ./cmd/ingest
     if [ $? -eq 0 ]; then
         print_success "CLI application built successfully"
     else
@@ -192,6 +192,23 @@ check_postgres() {
     fi
 }
 
+# Function to apply database migrations embedded in the CLI binary
+run_migrations() {
+    print_status "Applying database migrations..."
+
+    # Ensure SSL mode is set for local development
+    if [ "$SRV_DB_SSL_MODE" = "require" ]; then
+        export SRV_DB_SSL_MODE=disable
+    fi
+
+    if ./bin/ingest migrate up; then
+        print_success "Database schema is up to date"
+    else
+        print_error "Failed to apply database migrations"
+        exit 1
+    fi
+}
+
 # Function to run CLI application
 run_cli() {
     print_status "Starting CLI application for data ingestion..."
@@ -306,6 +323,9 @@ main() {
     
     # Build applications
     build_applications
+
+    # Apply database migrations (web and CLI refuse to run on an outdated schema)
+    run_migrations
     
     echo ""
     echo -e "${BLUE}================================${NC}"